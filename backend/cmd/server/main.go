@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"offermatrix/internal/config"
 	"offermatrix/internal/handler"
+	"offermatrix/internal/loginlimit"
 	"offermatrix/internal/middleware"
+	"offermatrix/internal/reminder"
+	"offermatrix/internal/repository"
+	"offermatrix/internal/webhook"
 	"offermatrix/pkg/database"
+	"offermatrix/pkg/jwt"
+	"offermatrix/pkg/mailer"
 )
 
 func main() {
@@ -18,11 +25,69 @@ func main() {
 		config.LoadDefault()
 	}
 
+	// 回调地址不能从请求推断，启用单点登录时必须显式配置
+	if config.AppConfig.OIDC.Enabled && config.AppConfig.OIDCRedirectURL() == "" {
+		log.Fatalf("OIDC is enabled but neither oidc.redirect_url nor server.base_url is configured")
+	}
+
+	if config.AppConfig.Mail.Host != "" && config.AppConfig.Server.BaseURL == "" {
+		log.Printf("server.base_url is not configured, password reset emails are disabled")
+	}
+
 	// Initialize database
 	if err := database.Init(); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// Initialize JWT signing keys
+	if err := jwt.Init(); err != nil {
+		log.Fatalf("Failed to initialize jwt signing: %v", err)
+	}
+	go jwt.RunRotation(context.Background())
+
+	// Bootstrap admins listed in config
+	promoted, err := repository.NewUserRepository().PromoteAdmins(config.AppConfig.Admin.Usernames)
+	if err != nil {
+		log.Fatalf("Failed to promote admins: %v", err)
+	}
+	if promoted > 0 {
+		log.Printf("Promoted %d user(s) to admin", promoted)
+	}
+
+	// Parse legacy free-text salaries into structured compensation
+	backfilled, err := repository.NewCompensationRepository().Backfill()
+	if err != nil {
+		log.Printf("Failed to backfill compensation: %v", err)
+	} else if backfilled > 0 {
+		log.Printf("Parsed compensation for %d application(s)", backfilled)
+	}
+
+	mailSender := newMailSender()
+
+	loginGuard, err := loginlimit.New(config.AppConfig.Login)
+	if err != nil {
+		log.Fatalf("Failed to initialize login limiter: %v", err)
+	}
+
+	// Start reminder scheduler
+	if cfg := config.AppConfig.Reminder; cfg.Enabled {
+		var channels []reminder.Channel
+		if cfg.Email {
+			channels = append(channels, reminder.NewEmailChannel(mailSender))
+		}
+		if cfg.Webhook.URL != "" {
+			channels = append(channels, reminder.NewWebhookChannel(cfg.Webhook.URL, cfg.Webhook.Secret))
+		}
+		scheduler, err := reminder.NewScheduler(cfg, channels...)
+		if err != nil {
+			log.Fatalf("Failed to initialize reminder scheduler: %v", err)
+		}
+		go scheduler.Run(context.Background())
+	}
+
+	// Start webhook delivery worker
+	go webhook.NewWorker().Run(context.Background())
+
 	// Setup Gin
 	r := gin.Default()
 
@@ -39,24 +104,60 @@ func main() {
 	api := r.Group("/api")
 	{
 		// Auth routes (public)
-		authHandler := handler.NewAuthHandler()
+		authHandler := handler.NewAuthHandler(mailSender, loginGuard)
 		authHandler.RegisterRoutes(api)
 
-		appHandler := handler.NewApplicationHandler()
-		appHandler.RegisterRoutes(api)
-
-		interviewHandler := handler.NewInterviewHandler()
-		interviewHandler.RegisterRoutes(api)
+		calendarHandler := handler.NewCalendarHandler()
+		calendarHandler.RegisterRoutes(api)
 	}
 
 	// Protected routes
 	protected := r.Group("/api")
 	protected.Use(middleware.AuthMiddleware())
 	{
-		authHandler := handler.NewAuthHandler()
+		authHandler := handler.NewAuthHandler(mailSender, loginGuard)
 		authHandler.RegisterProtectedRoutes(protected)
+
+		appHandler := handler.NewApplicationHandler()
+		appHandler.RegisterRoutes(protected)
+
+		interviewHandler := handler.NewInterviewHandler()
+		interviewHandler.RegisterRoutes(protected)
+
+		calendarHandler := handler.NewCalendarHandler()
+		calendarHandler.RegisterProtectedRoutes(protected)
+
+		webhookHandler := handler.NewWebhookHandler()
+		webhookHandler.RegisterRoutes(protected)
+
+		aiHandler := handler.NewAIHandler()
+		aiHandler.RegisterRoutes(protected)
+
+		statsHandler := handler.NewStatsHandler()
+		statsHandler.RegisterRoutes(protected)
+
+		workspaceHandler := handler.NewWorkspaceHandler()
+		workspaceHandler.RegisterRoutes(protected)
+
+		commentHandler := handler.NewCommentHandler()
+		commentHandler.RegisterRoutes(protected)
+
+		comparisonHandler := handler.NewComparisonHandler()
+		comparisonHandler.RegisterRoutes(protected)
+
+		offerHandler := handler.NewOfferHandler()
+		offerHandler.RegisterRoutes(protected)
+
+		contactHandler := handler.NewContactHandler()
+		contactHandler.RegisterRoutes(protected)
+
+		adminHandler := handler.NewAdminHandler()
+		adminHandler.RegisterRoutes(protected)
 	}
 
+	// Public keys for verifying issued tokens
+	r.GET("/.well-known/jwks.json", handler.JWKS)
+
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -69,3 +170,14 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+func newMailSender() mailer.Sender {
+	cfg := config.AppConfig.Mail
+	return mailer.New(mailer.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	})
+}
@@ -0,0 +1,166 @@
+// Package ai 组织面向业务的大模型调用：提示词、结构化输出解析和结果渲染
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"offermatrix/internal/config"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/llm"
+)
+
+type Assistant struct {
+	provider llm.Provider
+}
+
+// NewAssistant 使用配置中的大模型创建 Assistant，未配置时返回 llm.ErrNotConfigured
+func NewAssistant() (*Assistant, error) {
+	provider, err := llm.New(config.AppConfig.LLM)
+	if err != nil {
+		return nil, err
+	}
+	return &Assistant{provider: provider}, nil
+}
+
+func NewAssistantWithProvider(provider llm.Provider) *Assistant {
+	return &Assistant{provider: provider}
+}
+
+// Provider 返回底层的大模型
+func (a *Assistant) Provider() llm.Provider {
+	return a.provider
+}
+
+type parsedInvitationJSON struct {
+	CompanyName *string  `json:"company_name"`
+	RoundName   *string  `json:"round_name"`
+	StartTime   *string  `json:"start_time"`
+	EndTime     *string  `json:"end_time"`
+	MeetingLink *string  `json:"meeting_link"`
+	Confidence  *float64 `json:"confidence"`
+}
+
+// ParseInvitation 从面试通知文本中提取公司、轮次、时间和会议链接。
+// 模型输出的无时区时间按 now 所在时区解释
+func (a *Assistant) ParseInvitation(ctx context.Context, text string, now time.Time) (*model.ParsedInvitation, error) {
+	content, err := a.provider.Complete(ctx, llm.Request{
+		Task:        llm.TaskParseInvitation,
+		Messages:    []llm.Message{{Role: "user", Content: invitationPrompt(text, now)}},
+		Temperature: 0.1,
+		MaxTokens:   1024,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := llm.ExtractJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析 AI 返回的结果: %w", err)
+	}
+	var parsed parsedInvitationJSON
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+
+	result := &model.ParsedInvitation{
+		CompanyName: deref(parsed.CompanyName),
+		RoundName:   deref(parsed.RoundName),
+		MeetingLink: deref(parsed.MeetingLink),
+		StartTime:   parseModelTime(deref(parsed.StartTime), now.Location()),
+		EndTime:     parseModelTime(deref(parsed.EndTime), now.Location()),
+	}
+	if parsed.Confidence != nil {
+		result.Confidence = *parsed.Confidence
+	}
+	// 没有结束时间时按一小时推算
+	if result.StartTime != nil && result.EndTime == nil {
+		end := result.StartTime.Add(time.Hour)
+		result.EndTime = &end
+	}
+	return result, nil
+}
+
+// AnalyzeJD 分析职位描述，返回结构化结果和渲染好的 Markdown
+func (a *Assistant) AnalyzeJD(ctx context.Context, app *model.Application) (*model.JDAnalysisResult, error) {
+	content, err := a.provider.Complete(ctx, llm.Request{
+		Task:        llm.TaskAnalyzeJD,
+		Messages:    []llm.Message{{Role: "user", Content: jdAnalysisPrompt(app)}},
+		Temperature: 0.3,
+		MaxTokens:   4096,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := llm.ExtractJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("无法解析 AI 返回的结果: %w", err)
+	}
+	var result model.JDAnalysisResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+	}
+	result.Markdown = RenderJDAnalysis(&result)
+	return &result, nil
+}
+
+// StreamJDAnalysis 以 Markdown 流式分析 JD，onDelta 接收增量文本，返回完整结果
+func (a *Assistant) StreamJDAnalysis(ctx context.Context, app *model.Application, onDelta func(string) error) (string, error) {
+	return llm.Stream(ctx, a.provider, llm.Request{
+		Task:        llm.TaskAnalyzeJDStream,
+		Messages:    []llm.Message{{Role: "user", Content: jdAnalysisMarkdownPrompt(app)}},
+		Temperature: 0.3,
+		MaxTokens:   4096,
+	}, onDelta)
+}
+
+// RenderJDAnalysis 把结构化的 JD 分析渲染为 Markdown
+func RenderJDAnalysis(r *model.JDAnalysisResult) string {
+	var b strings.Builder
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "### %s\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+		b.WriteString("\n")
+	}
+
+	section("核心职责", r.Responsibilities)
+	section("技术栈与技能要求", r.Skills)
+	section("岗位优势", r.Advantages)
+	section("潜在顾虑", r.Concerns)
+	if r.SalaryAnalysis != "" {
+		fmt.Fprintf(&b, "### 薪资与市场分析\n\n%s\n", r.SalaryAnalysis)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+// parseModelTime 兼容模型常见的几种时间格式
+func parseModelTime(s string, loc *time.Location) *time.Time {
+	if s == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
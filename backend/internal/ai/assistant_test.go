@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"offermatrix/internal/model"
+	"offermatrix/pkg/llm"
+)
+
+func TestParseInvitationWithFake(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, loc)
+	a := NewAssistantWithProvider(llm.NewFake())
+
+	text := "【字节跳动】您好，邀请您参加技术二面，时间 2026-03-05 14:30，会议链接 https://meeting.tencent.com/dm/abc123 ，请准时参加。"
+	got, err := a.ParseInvitation(context.Background(), text, now)
+	if err != nil {
+		t.Fatalf("ParseInvitation() error = %v", err)
+	}
+
+	if got.CompanyName != "字节跳动" || got.RoundName != "技术二面" {
+		t.Errorf("company/round = %q/%q", got.CompanyName, got.RoundName)
+	}
+	if got.MeetingLink != "https://meeting.tencent.com/dm/abc123" {
+		t.Errorf("meeting link = %q", got.MeetingLink)
+	}
+	wantStart := time.Date(2026, 3, 5, 14, 30, 0, 0, loc)
+	if got.StartTime == nil || !got.StartTime.Equal(wantStart) {
+		t.Fatalf("start = %v, want %v", got.StartTime, wantStart)
+	}
+	if got.EndTime == nil || !got.EndTime.Equal(wantStart.Add(time.Hour)) {
+		t.Errorf("end = %v, want start + 1h", got.EndTime)
+	}
+
+	again, err := a.ParseInvitation(context.Background(), text, now)
+	if err != nil || *again.StartTime != *got.StartTime || again.CompanyName != got.CompanyName {
+		t.Errorf("fake provider is not deterministic: %+v vs %+v", again, got)
+	}
+}
+
+func TestParseInvitationMissingFields(t *testing.T) {
+	a := NewAssistantWithProvider(llm.NewFake())
+	got, err := a.ParseInvitation(context.Background(), "下周找时间聊聊", time.Now())
+	if err != nil {
+		t.Fatalf("ParseInvitation() error = %v", err)
+	}
+	if got.CompanyName != "" || got.StartTime != nil || got.EndTime != nil || got.MeetingLink != "" {
+		t.Errorf("expected empty result, got %+v", got)
+	}
+}
+
+func TestParseInvitationRejectsNonJSON(t *testing.T) {
+	fake := llm.NewFake()
+	fake.Responses[llm.TaskParseInvitation] = "抱歉，我无法识别"
+	a := NewAssistantWithProvider(fake)
+	if _, err := a.ParseInvitation(context.Background(), "任意文本", time.Now()); err == nil {
+		t.Fatal("expected error for non-JSON model output")
+	}
+}
+
+func TestAnalyzeJDWithFake(t *testing.T) {
+	a := NewAssistantWithProvider(llm.NewFake())
+	app := &model.Application{
+		CompanyName:    "美团",
+		JobTitle:       "后端开发",
+		JobDescription: "负责核心交易系统，熟悉 Go、MySQL、Redis，有微服务经验优先",
+	}
+
+	got, err := a.AnalyzeJD(context.Background(), app)
+	if err != nil {
+		t.Fatalf("AnalyzeJD() error = %v", err)
+	}
+	want := []string{"Go", "MySQL", "Redis", "微服务"}
+	if strings.Join(got.Skills, ",") != strings.Join(want, ",") {
+		t.Errorf("skills = %v, want %v", got.Skills, want)
+	}
+	for _, heading := range []string{"### 核心职责", "### 技术栈与技能要求", "- Redis", "### 薪资与市场分析"} {
+		if !strings.Contains(got.Markdown, heading) {
+			t.Errorf("markdown missing %q:\n%s", heading, got.Markdown)
+		}
+	}
+}
+
+func TestAnalyzeJDCannedResponse(t *testing.T) {
+	fake := llm.NewFake()
+	fake.Responses[llm.TaskAnalyzeJD] = "```json\n{\"responsibilities\":[\"写代码\"],\"skills\":[],\"salary_analysis\":\"偏高\"}\n```"
+	a := NewAssistantWithProvider(fake)
+
+	got, err := a.AnalyzeJD(context.Background(), &model.Application{JobDescription: "x"})
+	if err != nil {
+		t.Fatalf("AnalyzeJD() error = %v", err)
+	}
+	want := "### 核心职责\n\n- 写代码\n\n### 薪资与市场分析\n\n偏高"
+	if got.Markdown != want {
+		t.Errorf("markdown = %q, want %q", got.Markdown, want)
+	}
+}
+
+func TestStreamJDAnalysisWithFake(t *testing.T) {
+	a := NewAssistantWithProvider(llm.NewFake())
+	app := &model.Application{JobDescription: "需要 Kubernetes 和 Docker 经验"}
+
+	var b strings.Builder
+	text, err := a.StreamJDAnalysis(context.Background(), app, func(delta string) error {
+		b.WriteString(delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamJDAnalysis() error = %v", err)
+	}
+	if b.String() != text {
+		t.Errorf("concatenated deltas differ from returned text")
+	}
+	if !strings.Contains(text, "- Kubernetes") || !strings.Contains(text, "- Docker") {
+		t.Errorf("unexpected analysis:\n%s", text)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.StreamJDAnalysis(ctx, app, func(string) error { return nil }); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"fmt"
+	"time"
+
+	"offermatrix/internal/model"
+)
+
+var weekdayNames = []string{"日", "一", "二", "三", "四", "五", "六"}
+
+func invitationPrompt(text string, now time.Time) string {
+	return fmt.Sprintf(`你是一个面试信息提取助手。请从以下文本中提取面试相关信息。
+
+## 当前时间
+%s（星期%s），文本中的“明天”“下周三”等相对时间以此为准。
+
+## 提取要求
+1. company_name: 公司名称（如：字节跳动、腾讯、阿里巴巴等）
+2. round_name: 面试轮次（如：AI面、HR面、技术一面、业务二面、终面等）
+3. start_time: 开始时间（ISO 8601 格式，如 2024-02-08T14:00:00）
+4. end_time: 结束时间（ISO 8601 格式）
+5. meeting_link: 会议链接（Zoom、腾讯会议、飞书等）
+
+## 输出格式
+仅输出 JSON，不要其他内容：
+{
+  "company_name": "字节跳动",
+  "round_name": "技术一面",
+  "start_time": "2024-02-08T14:00:00",
+  "end_time": "2024-02-08T15:00:00",
+  "meeting_link": "https://...",
+  "confidence": 0.9
+}
+
+如果某字段无法提取，设为 null。confidence 表示整体置信度 (0-1)。
+如果没有明确的结束时间，可以根据开始时间推算（通常面试持续 30-60 分钟）。
+
+## 用户输入
+%s`, now.Format("2006-01-02 15:04"), weekdayNames[now.Weekday()], text)
+}
+
+func jdAnalysisPrompt(app *model.Application) string {
+	salary := app.Salary
+	if salary == "" {
+		salary = "未提供"
+	}
+
+	return fmt.Sprintf(`你是一位资深的职业顾问和行业分析师。请帮我分析这个职位，辅助我做求职和 Offer 决策。
+
+## 公司信息
+- 公司名称：%s
+- 职位名称：%s
+- 薪资待遇：%s
+
+## 职位描述（JD）
+%s
+
+## 分析要求
+请结合 JD 内容以及你对该公司和行业的了解进行分析：
+- responsibilities: 提炼 3-5 条核心工作职责
+- skills: 关键技术栈和能力要求
+- advantages: 岗位优势（成长空间、业务前景、平台和行业地位等）
+- concerns: 潜在顾虑（工作强度、技术天花板、业务稳定性等）
+- salary_analysis: 结合薪资信息和市场行情分析薪资竞争力，一段话
+
+## 输出格式
+仅输出 JSON，不要其他内容，数组元素为简洁的中文句子：
+{
+  "responsibilities": ["..."],
+  "skills": ["..."],
+  "advantages": ["..."],
+  "concerns": ["..."],
+  "salary_analysis": "..."
+}`, app.CompanyName, app.JobTitle, salary, app.JobDescription)
+}
+
+// jdAnalysisMarkdownPrompt 用于流式分析，直接输出 Markdown 便于逐字展示
+func jdAnalysisMarkdownPrompt(app *model.Application) string {
+	salary := app.Salary
+	if salary == "" {
+		salary = "未提供"
+	}
+
+	return fmt.Sprintf(`你是一位资深的职业顾问和行业分析师。请帮我分析这个职位，辅助我做求职和 Offer 决策。
+
+## 公司信息
+- 公司名称：%[1]s
+- 职位名称：%[2]s
+- 薪资待遇：%[3]s
+
+## 职位描述（JD）
+%[4]s
+
+## 分析要求
+请结合 JD 内容以及你对该公司和行业的了解，从以下几个维度进行分析。请用 Markdown 格式输出：
+
+### 核心职责
+提炼 3-5 条该职位的核心工作职责，用简洁的语言概括。
+
+### 技术栈与技能要求
+列出该职位涉及的关键技术栈和能力要求。
+
+### 岗位优势
+分析这个岗位的吸引力：技术成长空间、业务前景、公司平台和行业地位等。
+
+### 潜在顾虑
+客观分析可能的缺点或风险：工作强度、技术天花板、业务稳定性等。
+
+### 薪资与市场分析
+结合提供的薪资信息（%[3]s）和你对 %[1]s 该岗位市场行情的了解，分析薪资竞争力。
+
+请直接输出 Markdown 内容，不要包裹在代码块中。`, app.CompanyName, app.JobTitle, salary, app.JobDescription)
+}
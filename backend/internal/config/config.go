@@ -2,23 +2,123 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	JWT       JWTConfig       `yaml:"jwt"`
+	Login     LoginConfig     `yaml:"login"`
+	OIDC      OIDCConfig      `yaml:"oidc"`
+	Admin     AdminConfig     `yaml:"admin"`
+	Interview InterviewConfig `yaml:"interview"`
+	Mail      MailConfig      `yaml:"mail"`
+	Reminder  ReminderConfig  `yaml:"reminder"`
+	LLM       LLMConfig       `yaml:"llm"`
+	Currency  CurrencyConfig  `yaml:"currency"`
+}
+
+// CurrencyConfig 比较 offer 时使用的本地汇率表，不联网获取
+type CurrencyConfig struct {
+	// Base 默认折算到的币种
+	Base string `yaml:"base"`
+	// Rates 每 1 单位外币折合多少 Base，Base 自身不必列出
+	Rates map[string]float64 `yaml:"rates"`
+}
+
+// LLMConfig 服务端调用的大模型配置，provider 为空表示不启用 AI 功能
+type LLMConfig struct {
+	Provider       string `yaml:"provider"` // openai / qwen / zhipu / anthropic / fake
+	APIKey         string `yaml:"api_key"`
+	Model          string `yaml:"model"`
+	BaseURL        string `yaml:"base_url"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"`
+}
+
+// MailConfig SMTP 配置，host 为空时邮件只输出到日志
+type MailConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type ReminderConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 面试开始前多久提醒，Go duration 格式，例如 24h、30m
+	Offsets []string `yaml:"offsets"`
+	// 待答复的 offer 截止前多久提醒，格式同上
+	OfferOffsets        []string              `yaml:"offer_offsets"`
+	ScanIntervalSeconds int                   `yaml:"scan_interval_seconds"`
+	Email               bool                  `yaml:"email"`
+	Webhook             ReminderWebhookConfig `yaml:"webhook"`
+}
+
+type ReminderWebhookConfig struct {
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret"`
+}
+
+type InterviewConfig struct {
+	// 冲突检测时两场面试之间至少间隔的分钟数
+	ConflictBufferMinutes int `yaml:"conflict_buffer_minutes"`
 }
 
 type JWTConfig struct {
-	Secret     string `yaml:"secret"`
-	ExpireHour int    `yaml:"expire_hour"`
+	// 签名算法：EdDSA（默认）、RS256 使用密钥目录中自动轮换的密钥；HS256 使用 secret
+	Algorithm    string `yaml:"algorithm"`
+	KeysDir      string `yaml:"keys_dir"`
+	RotationDays int    `yaml:"rotation_days"`
+	Secret       string `yaml:"secret"`
+	// access token 有效期，过期后用 refresh token 换取新的
+	AccessTokenMinutes int `yaml:"access_token_minutes"`
+	// refresh token 有效期，每次刷新都会轮换并重新计时
+	RefreshTokenDays int `yaml:"refresh_token_days"`
+}
+
+// OIDCConfig 企业身份提供方单点登录配置
+type OIDCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Issuer 必须与发现文档中的 issuer 完全一致
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"`
+	// RedirectURL 为空时使用 server.base_url + /api/auth/oidc/callback，两者至少配置一个
+	RedirectURL string `yaml:"redirect_url"`
+	// AllowSignup 首次登录的用户是否自动创建账号
+	AllowSignup bool `yaml:"allow_signup"`
+	// LinkByEmail 是否把首次登录的用户关联到邮箱相同的已有账号，仅在身份提供方确认邮箱已验证时生效
+	LinkByEmail bool `yaml:"link_by_email"`
+	// ButtonText 登录页按钮文字
+	ButtonText string `yaml:"button_text"`
+}
+
+// AdminConfig 管理员配置
+type AdminConfig struct {
+	// Usernames 启动时提升为管理员的用户名，用于初始化第一个管理员
+	Usernames []string `yaml:"usernames"`
+}
+
+// LoginConfig 登录防暴力破解配置
+type LoginConfig struct {
+	// 失败计数的存储：memory 仅适用于单实例，多实例部署使用 database
+	Limiter string `yaml:"limiter"`
+	// 同一用户名连续失败多少次后锁定，以及锁定时长
+	LockoutThreshold int `yaml:"lockout_threshold"`
+	LockoutMinutes   int `yaml:"lockout_minutes"`
 }
 
 type ServerConfig struct {
 	Port string `yaml:"port"`
+	// Mode 为 dev 时放宽安全检查（例如允许默认的 JWT 密钥），生产环境留空
+	Mode string `yaml:"mode"`
+	// BaseURL 对外访问地址，用于生成日历订阅、重置密码等外部链接；为空时不发送重置邮件，订阅地址返回相对路径
+	BaseURL string `yaml:"base_url"`
 }
 
 type DatabaseConfig struct {
@@ -31,6 +131,18 @@ type DatabaseConfig struct {
 
 var AppConfig *Config
 
+// OIDCRedirectURL 返回单点登录回调地址，未配置 oidc.redirect_url 和 server.base_url 时返回空。
+// 不能根据请求的 Host 推断，否则第一个请求就能决定之后所有登录的回调地址
+func (c *Config) OIDCRedirectURL() string {
+	if c.OIDC.RedirectURL != "" {
+		return c.OIDC.RedirectURL
+	}
+	if c.Server.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(c.Server.BaseURL, "/") + "/api/auth/oidc/callback"
+}
+
 func Load(path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -59,8 +171,29 @@ func LoadDefault() {
 			Name:     "offermatrix",
 		},
 		JWT: JWTConfig{
-			Secret:     "offermatrix-secret-key",
-			ExpireHour: 168,
+			Algorithm:          "EdDSA",
+			KeysDir:            "keys",
+			RotationDays:       30,
+			AccessTokenMinutes: 15,
+			RefreshTokenDays:   30,
+		},
+		Login: LoginConfig{
+			Limiter:          "memory",
+			LockoutThreshold: 10,
+			LockoutMinutes:   15,
+		},
+		Interview: InterviewConfig{
+			ConflictBufferMinutes: 15,
+		},
+		Reminder: ReminderConfig{
+			Enabled:             true,
+			Offsets:             []string{"24h", "30m"},
+			OfferOffsets:        []string{"72h", "24h"},
+			ScanIntervalSeconds: 60,
+			Email:               true,
+		},
+		Currency: CurrencyConfig{
+			Base: "CNY",
 		},
 	}
 }
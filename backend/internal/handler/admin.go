@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"offermatrix/internal/middleware"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+	"offermatrix/pkg/jwt"
+	"offermatrix/pkg/securetoken"
+)
+
+// AdminHandler 管理后台接口，每个操作都写入审计日志
+type AdminHandler struct {
+	users    *repository.UserRepository
+	sessions *repository.SessionRepository
+	audit    *repository.AuditRepository
+}
+
+func NewAdminHandler() *AdminHandler {
+	return &AdminHandler{
+		users:    repository.NewUserRepository(),
+		sessions: repository.NewSessionRepository(),
+		audit:    repository.NewAuditRepository(),
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin", middleware.SessionOnly(), middleware.AdminOnly())
+	{
+		admin.GET("/users", h.ListUsers)
+		admin.GET("/users/:id", h.GetUser)
+		admin.PUT("/users/:id/role", h.UpdateRole)
+		admin.POST("/users/:id/disable", h.DisableUser)
+		admin.POST("/users/:id/enable", h.EnableUser)
+		admin.POST("/users/:id/reset-password", h.ResetPassword)
+		admin.POST("/users/:id/impersonate", h.Impersonate)
+		admin.GET("/audit-logs", h.ListAuditLogs)
+	}
+}
+
+// ListUsers godoc
+// @Summary List users with paging and an optional username/email keyword
+// @Param keyword query string false "Username or email keyword"
+// @Param page query int false "Page number, default 1"
+// @Param page_size query int false "Page size, default 20, max 100"
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	keyword := c.Query("keyword")
+	offset, limit := pagination(c)
+
+	users, total, err := h.users.List(keyword, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminListUsers, "", fmt.Sprintf("keyword=%q offset=%d", keyword, offset))
+
+	items := make([]model.AdminUserResponse, 0, len(users))
+	for i := range users {
+		items = append(items, newAdminUserResponse(&users[i]))
+	}
+	c.JSON(http.StatusOK, model.UserListResponse{Items: items, Total: total})
+}
+
+// GetUser godoc
+// @Summary Get a user with per-user usage counts
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	usage, err := h.users.Usage(user.ID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminViewUser, user.Username, "")
+
+	c.JSON(http.StatusOK, model.AdminUserDetailResponse{
+		User:  newAdminUserResponse(user),
+		Usage: *usage,
+	})
+}
+
+// UpdateRole godoc
+// @Summary Grant or revoke the admin role
+func (h *AdminHandler) UpdateRole(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 不允许撤销自己的管理员权限，避免系统中不再有管理员
+	if user.ID == c.GetInt64("userID") && req.Role != model.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不能撤销自己的管理员权限"})
+		return
+	}
+
+	if err := h.users.UpdateRole(user.ID, req.Role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminUpdateRole, user.Username, fmt.Sprintf("%s -> %s", user.Role, req.Role))
+
+	user.Role = req.Role
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// DisableUser godoc
+// @Summary Disable an account and revoke all its sessions and access tokens
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if user.ID == c.GetInt64("userID") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不能停用自己的账号"})
+		return
+	}
+
+	now := time.Now()
+	if err := h.users.Disable(user.ID, now); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminDisableUser, user.Username, "")
+
+	if user.DisabledAt == nil {
+		user.DisabledAt = &now
+	}
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// EnableUser godoc
+// @Summary Re-enable a disabled account
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.users.Enable(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminEnableUser, user.Username, "")
+
+	user.DisabledAt = nil
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// ResetPassword godoc
+// @Summary Set a new password, or generate a temporary one, and sign the user out everywhere
+func (h *AdminHandler) ResetPassword(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	// 请求体可以为空，表示生成临时密码
+	var req model.AdminResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	password := req.NewPassword
+	generated := password == ""
+	if generated {
+		random, _, err := securetoken.Generate("")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "生成临时密码失败"})
+			return
+		}
+		password = random[:12]
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "密码加密失败"})
+		return
+	}
+
+	if err := h.users.SetPassword(user.ID, string(hashedPassword), time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminResetPassword, user.Username, fmt.Sprintf("generated=%t", generated))
+
+	if generated {
+		c.JSON(http.StatusOK, gin.H{"temporary_password": password})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "password reset"})
+}
+
+// Impersonate godoc
+// @Summary Issue a short-lived read-only access token acting as the user, for support
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if user.IsAdmin() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不能代登录管理员账号"})
+		return
+	}
+
+	// 代登录单独建一个不可刷新的会话，出现在用户自己的会话列表里，可随时被吊销
+	now := time.Now()
+	adminID := c.GetInt64("userID")
+	session := &model.AuthSession{
+		UserID:     user.ID,
+		Device:     fmt.Sprintf("管理员只读代登录（%s）", c.GetString("username")),
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(jwt.ImpersonationTTL),
+	}
+	if err := h.sessions.Create(session, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := jwt.GenerateImpersonationToken(user.ID, user.Username, session.ID, adminID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 token 失败"})
+		return
+	}
+
+	h.record(c, model.AuditAdminImpersonate, user.Username, fmt.Sprintf("session=%d", session.ID))
+
+	c.JSON(http.StatusOK, model.ImpersonateResponse{
+		Token:     token,
+		ExpiresIn: int64(jwt.ImpersonationTTL.Seconds()),
+		User:      newUserResponse(user),
+	})
+}
+
+// ListAuditLogs godoc
+// @Summary Browse the audit log, newest first
+// @Param user_id query int false "Filter by acting user"
+// @Param action query string false "Filter by action, e.g. admin.disable_user"
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	userID, _ := strconv.ParseInt(c.Query("user_id"), 10, 64)
+	action := c.Query("action")
+	offset, limit := pagination(c)
+
+	entries, total, err := h.audit.List(userID, action, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.record(c, model.AuditAdminViewAudit, "", fmt.Sprintf("user_id=%d action=%q offset=%d", userID, action, offset))
+
+	c.JSON(http.StatusOK, model.AuditLogListResponse{Items: entries, Total: total})
+}
+
+// findUser 解析路径中的用户 ID，不存在时直接写 404
+func (h *AdminHandler) findUser(c *gin.Context) (*model.User, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+
+	user, err := h.users.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return nil, false
+	}
+	return user, true
+}
+
+// record 写入管理员操作审计，UserID 为执行操作的管理员
+func (h *AdminHandler) record(c *gin.Context, action, subject, detail string) {
+	entry := &model.AuditLog{
+		UserID:  c.GetInt64("userID"),
+		Action:  action,
+		Subject: subject,
+		IP:      c.ClientIP(),
+		Detail:  detail,
+	}
+	if err := h.audit.Create(entry); err != nil {
+		log.Printf("Failed to write audit log %s: %v", entry.Action, err)
+	}
+}
+
+// pagination 读取 page / page_size，返回 offset 和 limit
+func pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	size, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+	return (page - 1) * size, size
+}
+
+func newAdminUserResponse(user *model.User) model.AdminUserResponse {
+	return model.AdminUserResponse{
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		Role:             user.Role,
+		TwoFactorEnabled: user.TOTPEnabled,
+		SSO:              user.OIDCSubject != nil,
+		DisabledAt:       user.DisabledAt,
+		CreatedAt:        user.CreatedAt,
+	}
+}
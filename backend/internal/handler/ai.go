@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"offermatrix/internal/ai"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+	"offermatrix/pkg/llm"
+)
+
+type AIHandler struct {
+	assistant *ai.Assistant
+	appRepo   *repository.ApplicationRepository
+}
+
+func NewAIHandler() *AIHandler {
+	assistant, err := ai.NewAssistant()
+	if err != nil && !errors.Is(err, llm.ErrNotConfigured) {
+		log.Printf("Failed to initialize LLM provider, AI features disabled: %v", err)
+	}
+	return &AIHandler{
+		assistant: assistant,
+		appRepo:   repository.NewApplicationRepository(),
+	}
+}
+
+func (h *AIHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/ai/parse-invitation", h.ParseInvitation)
+	r.POST("/applications/:id/analyze-jd", h.AnalyzeJD)
+	r.POST("/applications/:id/analyze-jd/stream", h.StreamAnalyzeJD)
+}
+
+// ParseInvitation godoc
+// @Summary Extract company, round, time and meeting link from an interview invitation
+func (h *AIHandler) ParseInvitation(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	var req model.ParseInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.assistant.ParseInvitation(c.Request.Context(), req.Text, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	if result.CompanyName != "" {
+		apps, err := h.appRepo.FindAll(c.GetInt64("userID"))
+		if err == nil {
+			if app := matchByCompany(apps, result.CompanyName); app != nil {
+				result.MatchedApplicationID = app.ID
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AnalyzeJD godoc
+// @Summary Analyze the job description of an application and save the result
+func (h *AIHandler) AnalyzeJD(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	app, newJD, ok := h.loadForAnalysis(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.assistant.AnalyzeJD(c.Request.Context(), app)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	app, err = h.saveAnalysis(app, newJD, result.Markdown)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"application": app, "analysis": result})
+}
+
+// StreamAnalyzeJD godoc
+// @Summary Stream JD analysis as Server-Sent Events
+// @Description Emits "delta" events with text chunks, then "done" with the saved application,
+// @Description or "error". The result is only saved when the stream completes; a client
+// @Description disconnect cancels the upstream LLM call and discards the partial text.
+func (h *AIHandler) StreamAnalyzeJD(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	app, newJD, ok := h.loadForAnalysis(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// 关闭 nginx 缓冲，保证增量及时送达
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	text, err := h.assistant.StreamJDAnalysis(ctx, app, func(delta string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c.SSEvent("delta", gin.H{"text": delta})
+		c.Writer.Flush()
+		return nil
+	})
+	if ctx.Err() != nil {
+		// 客户端已断开，丢弃部分结果
+		return
+	}
+	if err != nil {
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+		return
+	}
+
+	app, err = h.saveAnalysis(app, newJD, text)
+	if err != nil {
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+		return
+	}
+
+	c.SSEvent("done", gin.H{"application": app})
+	c.Writer.Flush()
+}
+
+// loadForAnalysis 读取申请，请求体带 job_description 时先覆盖 JD，并返回新的 JD 供保存
+func (h *AIHandler) loadForAnalysis(c *gin.Context) (*model.Application, string, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, "", false
+	}
+
+	app, err := h.appRepo.FindByID(c.GetInt64("userID"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return nil, "", false
+	}
+
+	var req model.AnalyzeJDRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, "", false
+		}
+	}
+	if req.JobDescription != "" {
+		app.JobDescription = req.JobDescription
+	}
+	if app.JobDescription == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job_description is empty"})
+		return nil, "", false
+	}
+	return app, req.JobDescription, true
+}
+
+// saveAnalysis 只写回分析结果和新的 JD，然后重新读取申请，返回包含分析期间其他修改的最新数据
+func (h *AIHandler) saveAnalysis(app *model.Application, newJD, analysis string) (*model.Application, error) {
+	if err := h.appRepo.UpdateJDAnalysis(app.UserID, app.ID, newJD, analysis); err != nil {
+		return nil, err
+	}
+	return h.appRepo.FindByID(app.UserID, app.ID)
+}
+
+func (h *AIHandler) available(c *gin.Context) bool {
+	if h.assistant == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "LLM 未配置"})
+		return false
+	}
+	return true
+}
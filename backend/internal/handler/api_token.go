@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/securetoken"
+)
+
+const defaultAPITokenDays = 90
+
+// ListAPITokens godoc
+// @Summary List personal access tokens; plaintext is never returned again
+func (h *AuthHandler) ListAPITokens(c *gin.Context) {
+	tokens, err := h.apiTokens.FindByUser(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateAPIToken godoc
+// @Summary Create a named, scoped, expiring personal access token
+func (h *AuthHandler) CreateAPIToken(c *gin.Context) {
+	var req model.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultAPITokenDays
+	}
+
+	plain, hash, err := securetoken.Generate(model.APITokenPrefix)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成令牌失败"})
+		return
+	}
+
+	token := model.APIToken{
+		UserID:    c.GetInt64("userID"),
+		Name:      req.Name,
+		Scope:     req.Scope,
+		Prefix:    plain[:len(model.APITokenPrefix)+6],
+		TokenHash: hash,
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := h.apiTokens.Create(&token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.CreateAPITokenResponse{
+		APIToken: token,
+		Token:    plain,
+	})
+}
+
+// RevokeAPIToken godoc
+// @Summary Revoke a personal access token immediately
+func (h *AuthHandler) RevokeAPIToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.apiTokens.Revoke(c.GetInt64("userID"), id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "revoked"})
+}
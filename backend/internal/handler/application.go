@@ -1,21 +1,34 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"offermatrix/internal/model"
 	"offermatrix/internal/repository"
+	"offermatrix/internal/webhook"
 )
 
+// ApplicationHandler 的查询和修改接口支持 workspace_id 参数，供教练访问求职者的数据；
+// 新建和删除只能由求职者本人操作
 type ApplicationHandler struct {
-	repo *repository.ApplicationRepository
+	repo          *repository.ApplicationRepository
+	compensations *repository.CompensationRepository
+	workspaces    *repository.WorkspaceRepository
+	events        *webhook.Dispatcher
 }
 
 func NewApplicationHandler() *ApplicationHandler {
-	return &ApplicationHandler{repo: repository.NewApplicationRepository()}
+	return &ApplicationHandler{
+		repo:          repository.NewApplicationRepository(),
+		compensations: repository.NewCompensationRepository(),
+		workspaces:    repository.NewWorkspaceRepository(),
+		events:        webhook.NewDispatcher(),
+	}
 }
 
 func (h *ApplicationHandler) RegisterRoutes(r *gin.RouterGroup) {
@@ -23,17 +36,25 @@ func (h *ApplicationHandler) RegisterRoutes(r *gin.RouterGroup) {
 	{
 		apps.GET("", h.List)
 		apps.GET("/:id", h.Get)
+		apps.GET("/:id/timeline", h.Timeline)
 		apps.POST("", h.Create)
 		apps.PUT("/:id", h.Update)
 		apps.DELETE("/:id", h.Delete)
 	}
+	r.POST("/salary/parse", h.ParseSalary)
 }
 
 // List godoc
 // @Summary List all applications
 // @Param keyword query string false "Search keyword"
 // @Param status query string false "Status filter (comma-separated: IN_PROCESS,OFFER,REJECTED)"
+// @Param workspace_id query int false "List a coached seeker's applications"
 func (h *ApplicationHandler) List(c *gin.Context) {
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
 	keyword := c.Query("keyword")
 	statusParam := c.Query("status")
 
@@ -42,7 +63,7 @@ func (h *ApplicationHandler) List(c *gin.Context) {
 		statuses = strings.Split(statusParam, ",")
 	}
 
-	apps, err := h.repo.SearchWithFilters(keyword, statuses)
+	apps, err := h.repo.SearchWithFilters(access.OwnerID, keyword, statuses)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -61,11 +82,17 @@ func (h *ApplicationHandler) Get(c *gin.Context) {
 		return
 	}
 
-	app, err := h.repo.FindByID(id)
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	app, err := h.repo.FindByID(access.OwnerID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
 		return
 	}
+	access.redactReviews(app.Interviews)
 
 	c.JSON(http.StatusOK, app)
 }
@@ -80,25 +107,32 @@ func (h *ApplicationHandler) Create(c *gin.Context) {
 	}
 
 	app := &model.Application{
+		UserID:        c.GetInt64("userID"),
 		CompanyName:   req.CompanyName,
 		JobTitle:      req.JobTitle,
 		CurrentStatus: req.CurrentStatus,
 	}
 
 	if app.CurrentStatus == "" {
-		app.CurrentStatus = "IN_PROCESS"
+		app.CurrentStatus = model.StatusInProcess
+	}
+	if !model.IsValidStatus(app.CurrentStatus) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown status " + app.CurrentStatus})
+		return
 	}
 
-	if err := h.repo.Create(app); err != nil {
+	if err := h.repo.Create(app, req.StatusReason); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	h.events.Emit(app.UserID, model.EventApplicationCreated, app)
+
 	c.JSON(http.StatusCreated, app)
 }
 
 // Update godoc
-// @Summary Update an application
+// @Summary Update an application; coaches with edit_pipeline may update everything except salary and compensation
 func (h *ApplicationHandler) Update(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -106,7 +140,12 @@ func (h *ApplicationHandler) Update(c *gin.Context) {
 		return
 	}
 
-	app, err := h.repo.FindByID(id)
+	access, ok := resolveAccess(c, h.workspaces, model.PermEditPipeline)
+	if !ok {
+		return
+	}
+
+	app, err := h.repo.FindByID(access.OwnerID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
 		return
@@ -118,18 +157,51 @@ func (h *ApplicationHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if (req.Salary != "" || req.Compensation != nil) && !access.IsOwner() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "薪资只能由本人修改"})
+		return
+	}
+
 	if req.CompanyName != "" {
 		app.CompanyName = req.CompanyName
 	}
 	if req.JobTitle != "" {
 		app.JobTitle = req.JobTitle
 	}
-	if req.CurrentStatus != "" {
+	var event *model.ApplicationStatusEvent
+	if req.CurrentStatus != "" && req.CurrentStatus != app.CurrentStatus {
+		if err := model.ValidateTransition(app.CurrentStatus, req.CurrentStatus); err != nil {
+			respondTransitionError(c, err)
+			return
+		}
+		event = &model.ApplicationStatusEvent{
+			ApplicationID: app.ID,
+			UserID:        app.UserID,
+			FromStatus:    app.CurrentStatus,
+			ToStatus:      req.CurrentStatus,
+			Reason:        req.StatusReason,
+		}
 		app.CurrentStatus = req.CurrentStatus
 	}
+	// 结构化薪资：手动填写优先，否则从新的薪资文本解析；解析不了时删除旧记录，避免与文本不一致
+	var compensation *model.Compensation
+	clearCompensation := false
+	if req.Compensation != nil {
+		compensation, err = req.Compensation.Build()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else if req.Salary != "" && req.Salary != app.Salary {
+		compensation, err = model.ParseCompensation(req.Salary)
+		clearCompensation = err != nil
+	}
 	if req.Salary != "" {
 		app.Salary = req.Salary
 	}
+	if req.City != "" {
+		app.City = req.City
+	}
 	if req.JobDescription != "" {
 		app.JobDescription = req.JobDescription
 	}
@@ -137,14 +209,86 @@ func (h *ApplicationHandler) Update(c *gin.Context) {
 		app.JDAnalysis = req.JDAnalysis
 	}
 
-	if err := h.repo.Update(app); err != nil {
+	if err := h.repo.Update(app, event); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if compensation != nil {
+		compensation.ApplicationID = app.ID
+		compensation.UserID = app.UserID
+		if err := h.compensations.Save(compensation); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		app.Compensation = compensation
+	} else if clearCompensation {
+		if err := h.compensations.DeleteByApplication(app.UserID, app.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		app.Compensation = nil
+	}
+
+	if event != nil {
+		h.events.Emit(app.UserID, model.EventApplicationStatusChanged, gin.H{
+			"application": app,
+			"from_status": event.FromStatus,
+			"to_status":   event.ToStatus,
+			"reason":      event.Reason,
+		})
+	}
+
+	access.redactReviews(app.Interviews)
 	c.JSON(http.StatusOK, app)
 }
 
+// ParseSalary godoc
+// @Summary Preview the structured compensation parsed from salary text such as "30-40K·16薪"
+func (h *ApplicationHandler) ParseSalary(c *gin.Context) {
+	var req model.ParseSalaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	compensation, err := model.ParseCompensation(req.Text)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无法识别的薪资格式"})
+		return
+	}
+
+	c.JSON(http.StatusOK, compensation)
+}
+
+// Timeline godoc
+// @Summary List status transitions of an application in chronological order
+func (h *ApplicationHandler) Timeline(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	if !h.repo.Exists(access.OwnerID, id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	events, err := h.repo.FindStatusEvents(access.OwnerID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
 // Delete godoc
 // @Summary Delete an application
 func (h *ApplicationHandler) Delete(c *gin.Context) {
@@ -154,10 +298,32 @@ func (h *ApplicationHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.Delete(c.GetInt64("userID"), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
+
+func respondTransitionError(c *gin.Context, err error) {
+	var transitionErr *model.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		allowed := transitionErr.Allowed
+		if allowed == nil {
+			allowed = []string{}
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   transitionErr.Error(),
+			"from":    transitionErr.From,
+			"to":      transitionErr.To,
+			"allowed": allowed,
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
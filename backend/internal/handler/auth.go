@@ -1,21 +1,68 @@
 package handler
 
 import (
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"offermatrix/internal/loginlimit"
+	"offermatrix/internal/middleware"
 	"offermatrix/internal/model"
 	"offermatrix/internal/repository"
 	"offermatrix/pkg/jwt"
+	"offermatrix/pkg/mailer"
+	"offermatrix/pkg/oidc"
+	"offermatrix/pkg/securetoken"
 )
 
+// refresh token 明文前缀，便于在日志和密钥扫描中识别
+const refreshTokenPrefix = "omr_"
+
+const errAccountDisabled = "账号已被停用，请联系管理员"
+
+// dummyPasswordHash 在用户名不存在时参与比对，成本与真实密码哈希相同
+var dummyPasswordHash = mustHashPassword("offermatrix-dummy-password")
+
+func mustHashPassword(password string) []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
+
 type AuthHandler struct {
-	repo *repository.UserRepository
+	repo      *repository.UserRepository
+	sessions  *repository.SessionRepository
+	apiTokens *repository.APITokenRepository
+	audit     *repository.AuditRepository
+	mailer    mailer.Sender
+	guard     *loginlimit.Guard
+
+	oidcStates *repository.OIDCStateRepository
+	oidcMu     sync.Mutex
+	oidc       *oidc.Provider
 }
 
-func NewAuthHandler() *AuthHandler {
-	return &AuthHandler{repo: repository.NewUserRepository()}
+// NewAuthHandler 的 sender 用于发送找回密码邮件；guard 记录登录失败，
+// 使用内存计数时公开路由和受保护路由的 handler 必须共用同一个 guard
+func NewAuthHandler(sender mailer.Sender, guard *loginlimit.Guard) *AuthHandler {
+	return &AuthHandler{
+		repo:      repository.NewUserRepository(),
+		sessions:  repository.NewSessionRepository(),
+		apiTokens: repository.NewAPITokenRepository(),
+		audit:     repository.NewAuditRepository(),
+		mailer:    sender,
+		guard:     guard,
+
+		oidcStates: repository.NewOIDCStateRepository(),
+	}
 }
 
 func (h *AuthHandler) RegisterRoutes(r *gin.RouterGroup) {
@@ -23,6 +70,15 @@ func (h *AuthHandler) RegisterRoutes(r *gin.RouterGroup) {
 	{
 		auth.POST("/register", h.Register)
 		auth.POST("/login", h.Login)
+		auth.POST("/login/2fa", h.LoginTwoFactor)
+		auth.POST("/refresh", h.Refresh)
+		auth.POST("/logout", h.Logout)
+		auth.POST("/password/forgot", h.ForgotPassword)
+		auth.POST("/password/reset", h.ResetPassword)
+		auth.GET("/oidc/config", h.OIDCConfig)
+		auth.GET("/oidc/login", h.OIDCLogin)
+		auth.GET("/oidc/callback", h.OIDCCallback)
+		auth.POST("/oidc/exchange", h.OIDCExchange)
 	}
 }
 
@@ -31,6 +87,25 @@ func (h *AuthHandler) RegisterProtectedRoutes(r *gin.RouterGroup) {
 	{
 		auth.GET("/me", h.GetCurrentUser)
 	}
+
+	// 账号安全相关接口不接受个人访问令牌
+	account := r.Group("/auth", middleware.SessionOnly())
+	{
+		account.PUT("/me", h.UpdateProfile)
+		account.DELETE("/me", h.DeleteAccount)
+		account.PUT("/password", h.ChangePassword)
+		account.GET("/2fa", h.TwoFactorStatus)
+		account.POST("/2fa/setup", h.SetupTwoFactor)
+		account.POST("/2fa/enable", h.EnableTwoFactor)
+		account.POST("/2fa/disable", h.DisableTwoFactor)
+		account.POST("/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+		account.GET("/sessions", h.ListSessions)
+		account.DELETE("/sessions", h.RevokeOtherSessions)
+		account.DELETE("/sessions/:id", h.RevokeSession)
+		account.GET("/tokens", h.ListAPITokens)
+		account.POST("/tokens", h.CreateAPIToken)
+		account.DELETE("/tokens/:id", h.RevokeAPIToken)
+	}
 }
 
 // Register 用户注册
@@ -57,6 +132,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	user := &model.User{
 		Username: req.Username,
 		Password: string(hashedPassword),
+		Email:    req.Email,
+		Role:     model.RoleUser,
 	}
 
 	if err := h.repo.Create(user); err != nil {
@@ -64,11 +141,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		CreatedAt: user.CreatedAt,
-	})
+	c.JSON(http.StatusCreated, newUserResponse(user))
 }
 
 // Login 用户登录
@@ -79,36 +152,165 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 查找用户
+	now := time.Now()
+	ip := c.ClientIP()
+	decision, err := h.guard.Check(req.Username, ip, now)
+	if err != nil {
+		log.Printf("Failed to check login limiter: %v", err)
+	} else if !decision.Allowed {
+		respondLoginThrottled(c, decision)
+		return
+	}
+
+	// 查找用户；用户名不存在时同样计入失败，并比对一个固定的哈希，
+	// 让响应内容和耗时都与密码错误一致，避免借此探测用户名
 	user, err := h.repo.FindByUsername(req.Username)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	} else {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+	}
 	if err != nil {
+		h.recordLoginFailure(req.Username, ip, user, now)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户名或密码错误"})
 		return
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户名或密码错误"})
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": errAccountDisabled})
+		return
+	}
+
+	// 开启两步验证的账号先返回 challenge token，验证码通过后才签发正式 token，
+	// 失败计数也留到那时再清零，避免凭已知密码反复刷新计数来暴力猜验证码
+	if user.TOTPEnabled {
+		challenge, err := jwt.GenerateChallengeToken(user.ID, user.Username)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 token 失败"})
+			return
+		}
+		c.JSON(http.StatusOK, model.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresIn:         int64(jwt.ChallengeTTL.Seconds()),
+		})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+// completeLogin 清除失败计数，创建会话并返回 token
+func (h *AuthHandler) completeLogin(c *gin.Context, user *model.User) {
+	// 两步验证、单点登录等路径也经过这里，停用状态在签发 token 前统一再检查一次
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": errAccountDisabled})
 		return
 	}
 
-	// 生成 token
-	token, err := jwt.GenerateToken(user.ID, user.Username)
+	if err := h.guard.Succeed(user.Username); err != nil {
+		log.Printf("Failed to reset login limiter for %q: %v", user.Username, err)
+	}
+
+	// 创建会话并签发 token
+	tokens, err := h.startSession(c, user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 token 失败"})
 		return
 	}
 
 	c.JSON(http.StatusOK, model.LoginResponse{
-		Token: token,
-		User: model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			CreatedAt: user.CreatedAt,
-		},
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         newUserResponse(user),
 	})
 }
 
+// Refresh 用 refresh token 换取新的 access token，并轮换 refresh token。
+// 已轮换过的 refresh token 再次出现说明可能泄露，整个会话随之吊销
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req model.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	old, err := h.sessions.FindRefreshToken(securetoken.Hash(req.RefreshToken))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的 refresh token"})
+		return
+	}
+
+	session, err := h.sessions.FindByID(old.SessionID)
+	if err != nil || session.RevokedAt != nil || !now.Before(old.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "登录已失效，请重新登录"})
+		return
+	}
+
+	if old.UsedAt != nil {
+		h.revokeReusedSession(session.ID, now)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "登录已失效，请重新登录"})
+		return
+	}
+
+	user, err := h.repo.FindByID(old.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	plain, next, err := newRefreshToken(now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 token 失败"})
+		return
+	}
+
+	rotated, err := h.sessions.Rotate(old, next, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "刷新 token 失败"})
+		return
+	}
+	if !rotated {
+		// 并发请求抢先使用了同一枚 refresh token
+		h.revokeReusedSession(session.ID, now)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "登录已失效，请重新登录"})
+		return
+	}
+
+	token, err := jwt.GenerateToken(user.ID, user.Username, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 token 失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TokenResponse{
+		Token:        token,
+		RefreshToken: plain,
+		ExpiresIn:    int64(jwt.AccessTTL().Seconds()),
+	})
+}
+
+// Logout 吊销 refresh token 所属的会话，该会话签发的 access token 随即失效。
+// 令牌无效时同样返回成功，避免泄露令牌是否存在
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req model.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if token, err := h.sessions.FindRefreshToken(securetoken.Hash(req.RefreshToken)); err == nil {
+		if err := h.sessions.Revoke(token.SessionID, model.SessionRevokedLogout, time.Now()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "退出登录失败"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
 // GetCurrentUser 获取当前用户信息
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	userID := c.GetInt64("userID")
@@ -119,9 +321,145 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		CreatedAt: user.CreatedAt,
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// UpdateProfile 更新当前用户资料
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	var req model.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if err := h.repo.UpdateEmail(userID, req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新用户信息失败"})
+		return
+	}
+
+	user, err := h.repo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// startSession 为登录成功的用户创建会话，返回 access token 和第一枚 refresh token
+func (h *AuthHandler) startSession(c *gin.Context, user *model.User) (*model.TokenResponse, error) {
+	now := time.Now()
+	plain, refresh, err := newRefreshToken(now)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := c.Request.UserAgent()
+	session := &model.AuthSession{
+		UserID:     user.ID,
+		Device:     describeDevice(userAgent),
+		UserAgent:  userAgent,
+		IP:         c.ClientIP(),
+		LastSeenAt: now,
+		ExpiresAt:  refresh.ExpiresAt,
+	}
+	if err := h.sessions.Create(session, refresh); err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.GenerateToken(user.ID, user.Username, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		Token:        token,
+		RefreshToken: plain,
+		ExpiresIn:    int64(jwt.AccessTTL().Seconds()),
+	}, nil
+}
+
+// recordLoginFailure 记录失败次数，触发锁定时写入审计日志
+func (h *AuthHandler) recordLoginFailure(username, ip string, user *model.User, now time.Time) {
+	userStatus, ipStatus, err := h.guard.Fail(username, ip, now)
+	if err != nil {
+		log.Printf("Failed to record login failure: %v", err)
+		return
+	}
+
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+	if userStatus.Locked {
+		h.writeAudit(&model.AuditLog{
+			UserID:  userID,
+			Action:  model.AuditLoginLocked,
+			Subject: username,
+			IP:      ip,
+			Detail:  fmt.Sprintf("%d failed attempts, locked until %s", userStatus.Failures, userStatus.RetryAt.Format(time.RFC3339)),
+		})
+	}
+	if ipStatus.Locked {
+		h.writeAudit(&model.AuditLog{
+			Action:  model.AuditLoginIPBlocked,
+			Subject: ip,
+			IP:      ip,
+			Detail:  fmt.Sprintf("%d failed attempts, blocked until %s", ipStatus.Failures, ipStatus.RetryAt.Format(time.RFC3339)),
+		})
+	}
+}
+
+func (h *AuthHandler) writeAudit(entry *model.AuditLog) {
+	if err := h.audit.Create(entry); err != nil {
+		log.Printf("Failed to write audit log %s: %v", entry.Action, err)
+	}
+}
+
+// respondLoginThrottled 账号锁定返回 423，退避或来源 IP 限流返回 429，均带 Retry-After
+func respondLoginThrottled(c *gin.Context, decision loginlimit.Decision) {
+	seconds := int(math.Ceil(decision.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	if decision.Locked {
+		c.JSON(http.StatusLocked, gin.H{
+			"error":       fmt.Sprintf("登录失败次数过多，账号已临时锁定，请 %d 分钟后再试", int(math.Ceil(decision.RetryAfter.Minutes()))),
+			"retry_after": seconds,
+		})
+		return
+	}
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       fmt.Sprintf("登录尝试过于频繁，请 %d 秒后再试", seconds),
+		"retry_after": seconds,
 	})
 }
+
+func (h *AuthHandler) revokeReusedSession(sessionID int64, now time.Time) {
+	log.Printf("Refresh token reuse detected, revoking session %d", sessionID)
+	if err := h.sessions.Revoke(sessionID, model.SessionRevokedReuseDetected, now); err != nil {
+		log.Printf("Failed to revoke session %d: %v", sessionID, err)
+	}
+}
+
+// newRefreshToken 生成 refresh token，返回明文和待入库的记录
+func newRefreshToken(now time.Time) (string, *model.RefreshToken, error) {
+	plain, hash, err := securetoken.Generate(refreshTokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	return plain, &model.RefreshToken{
+		TokenHash: hash,
+		ExpiresAt: now.Add(jwt.RefreshTTL()),
+	}, nil
+}
+
+func newUserResponse(user *model.User) model.UserResponse {
+	return model.UserResponse{
+		ID:               user.ID,
+		Username:         user.Username,
+		Email:            user.Email,
+		Role:             user.Role,
+		TwoFactorEnabled: user.TOTPEnabled,
+		CreatedAt:        user.CreatedAt,
+	}
+}
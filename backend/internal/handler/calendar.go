@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"offermatrix/internal/config"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+	"offermatrix/pkg/ical"
+	"offermatrix/pkg/securetoken"
+)
+
+// 日历订阅密钥明文前缀
+const calendarTokenPrefix = "omc_"
+
+// 订阅源覆盖的时间窗口
+const (
+	feedLookback  = 90 * 24 * time.Hour
+	feedLookahead = 365 * 24 * time.Hour
+)
+
+type CalendarHandler struct {
+	interviewRepo *repository.InterviewRepository
+	appRepo       *repository.ApplicationRepository
+	userRepo      *repository.UserRepository
+}
+
+func NewCalendarHandler() *CalendarHandler {
+	return &CalendarHandler{
+		interviewRepo: repository.NewInterviewRepository(),
+		appRepo:       repository.NewApplicationRepository(),
+		userRepo:      repository.NewUserRepository(),
+	}
+}
+
+// RegisterRoutes 注册公开路由，订阅链接通过 URL 中的密钥鉴权，供日历客户端轮询
+func (h *CalendarHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/calendar/feed/:token", h.Feed)
+}
+
+func (h *CalendarHandler) RegisterProtectedRoutes(r *gin.RouterGroup) {
+	cal := r.Group("/calendar")
+	{
+		cal.GET("/export", h.ExportRange)
+		cal.GET("/interviews/:id", h.ExportInterview)
+		cal.GET("/applications/:id", h.ExportApplication)
+		cal.GET("/subscription", h.GetSubscription)
+		cal.POST("/subscription", h.RotateSubscription)
+		cal.DELETE("/subscription", h.DisableSubscription)
+	}
+}
+
+// ExportInterview godoc
+// @Summary Export a single interview as .ics
+func (h *CalendarHandler) ExportInterview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	interview, err := h.interviewRepo.FindByID(c.GetInt64("userID"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
+		return
+	}
+
+	writeCalendar(c, fmt.Sprintf("interview-%d.ics", id), "", []model.Interview{*interview})
+}
+
+// ExportApplication godoc
+// @Summary Export all interviews of an application as .ics
+func (h *CalendarHandler) ExportApplication(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	app, err := h.appRepo.FindByID(c.GetInt64("userID"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	// 预加载的面试不带 Application，补上以便生成标题
+	interviews := make([]model.Interview, len(app.Interviews))
+	for i, interview := range app.Interviews {
+		interview.Application = app
+		interviews[i] = interview
+	}
+
+	writeCalendar(c, fmt.Sprintf("application-%d.ics", id), app.CompanyName+" 面试", interviews)
+}
+
+// ExportRange godoc
+// @Summary Export interviews in a time range as .ics
+// @Param start query string true "Start time (RFC3339 or 2006-01-02)"
+// @Param end query string true "End time (RFC3339 or 2006-01-02)"
+func (h *CalendarHandler) ExportRange(c *gin.Context) {
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+	if startStr == "" || endStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start and end are required"})
+		return
+	}
+
+	start, end, err := parseTimeRange(startStr, endStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interviews, err := h.interviewRepo.FindByTimeRange(c.GetInt64("userID"), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeCalendar(c, "interviews.ics", "OfferMatrix 面试", interviews)
+}
+
+// Feed godoc
+// @Summary Calendar subscription feed authenticated by the secret token in the URL
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	user, err := h.userRepo.FindByCalendarToken(securetoken.Hash(token))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "feed not found"})
+		return
+	}
+
+	now := time.Now()
+	interviews, err := h.interviewRepo.FindByTimeRange(user.ID, now.Add(-feedLookback), now.Add(feedLookahead))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	writeCalendar(c, "offermatrix.ics", "OfferMatrix 面试", interviews)
+}
+
+// GetSubscription godoc
+// @Summary Get the calendar subscription status, creating the secret token on first use
+// @Description Only the hash is stored, so the URL is returned once when the token is created; rotate to get a new one
+func (h *CalendarHandler) GetSubscription(c *gin.Context) {
+	user, err := h.userRepo.FindByID(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	if user.CalendarTokenHash != "" {
+		c.JSON(http.StatusOK, gin.H{"enabled": true})
+		return
+	}
+
+	token, err := h.issueToken(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "url": feedURL(token)})
+}
+
+// RotateSubscription godoc
+// @Summary Regenerate the subscription token, invalidating the previous URL
+// @Description The new URL is only shown in this response
+func (h *CalendarHandler) RotateSubscription(c *gin.Context) {
+	token, err := h.issueToken(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "url": feedURL(token)})
+}
+
+// DisableSubscription godoc
+// @Summary Disable the calendar subscription
+func (h *CalendarHandler) DisableSubscription(c *gin.Context) {
+	if err := h.userRepo.UpdateCalendarToken(c.GetInt64("userID"), ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "disabled"})
+}
+
+// issueToken 生成新的订阅密钥，数据库只保存摘要，返回的明文需要立即交给用户
+func (h *CalendarHandler) issueToken(userID int64) (string, error) {
+	plain, hash, err := securetoken.Generate(calendarTokenPrefix)
+	if err != nil {
+		return "", err
+	}
+	if err := h.userRepo.UpdateCalendarToken(userID, hash); err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// feedURL 未配置 server.base_url 时返回相对路径，由客户端补全为当前站点地址
+func feedURL(token string) string {
+	return publicBaseURL() + "/api/calendar/feed/" + token + ".ics"
+}
+
+// publicBaseURL 返回配置的 server.base_url，未配置时为空。
+// 不根据请求的 Host 推断，否则伪造 Host 就能让生成的链接指向任意域名
+func publicBaseURL() string {
+	return strings.TrimRight(config.AppConfig.Server.BaseURL, "/")
+}
+
+func writeCalendar(c *gin.Context, filename, name string, interviews []model.Interview) {
+	cal := &ical.Calendar{Name: name}
+	for _, interview := range interviews {
+		cal.Events = append(cal.Events, interviewEvent(interview))
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", cal.Marshal())
+}
+
+// interviewEvent 将面试映射为 VEVENT，UID 只由面试 ID 决定，客户端据此覆盖而不是重复添加
+func interviewEvent(interview model.Interview) ical.Event {
+	company, jobTitle := "", ""
+	if interview.Application != nil {
+		company = interview.Application.CompanyName
+		jobTitle = interview.Application.JobTitle
+	}
+
+	summary := interview.RoundName
+	if company != "" {
+		summary = company + " · " + interview.RoundName
+	}
+
+	var desc []string
+	if jobTitle != "" {
+		desc = append(desc, "岗位: "+jobTitle)
+	}
+	desc = append(desc, "轮次: "+interview.RoundName, "状态: "+interview.Status)
+	if interview.MeetingLink != "" {
+		desc = append(desc, "会议链接: "+interview.MeetingLink)
+	}
+	if interview.Notes != "" {
+		desc = append(desc, "", interview.Notes)
+	}
+
+	status := "CONFIRMED"
+	if interview.Status == model.InterviewStatusCancelled {
+		status = "CANCELLED"
+	}
+
+	event := ical.Event{
+		UID:          fmt.Sprintf("interview-%d@offermatrix", interview.ID),
+		Summary:      summary,
+		Description:  strings.Join(desc, "\n"),
+		Location:     interview.MeetingLink,
+		Status:       status,
+		Start:        interview.StartTime,
+		End:          interview.EndTime,
+		Stamp:        interview.UpdatedAt,
+		LastModified: interview.UpdatedAt,
+	}
+	if strings.HasPrefix(interview.MeetingLink, "http://") || strings.HasPrefix(interview.MeetingLink, "https://") {
+		event.URL = interview.MeetingLink
+	}
+	return event
+}
@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_.\-]+)`)
+
+// CommentHandler 面试复盘上的讨论。求职者本人随时可以评论；教练需要 view_reviews 权限查看、
+// comment_reviews 权限发表。评论只能由作者修改，作者和求职者本人可以删除
+type CommentHandler struct {
+	repo       *repository.CommentRepository
+	interviews *repository.InterviewRepository
+	workspaces *repository.WorkspaceRepository
+	users      *repository.UserRepository
+}
+
+func NewCommentHandler() *CommentHandler {
+	return &CommentHandler{
+		repo:       repository.NewCommentRepository(),
+		interviews: repository.NewInterviewRepository(),
+		workspaces: repository.NewWorkspaceRepository(),
+		users:      repository.NewUserRepository(),
+	}
+}
+
+func (h *CommentHandler) RegisterRoutes(r *gin.RouterGroup) {
+	comments := r.Group("/interviews/:id/comments")
+	{
+		comments.GET("", h.List)
+		comments.POST("", h.Create)
+		comments.POST("/read", h.MarkRead)
+		comments.PUT("/:comment_id", h.Update)
+		comments.DELETE("/:comment_id", h.Delete)
+	}
+	r.GET("/comments/unread", h.Unread)
+}
+
+// List godoc
+// @Summary List the comment threads on an interview review
+// @Param workspace_id query int false "Coached seeker's workspace"
+func (h *CommentHandler) List(c *gin.Context) {
+	_, interview, ok := h.findInterview(c, model.PermViewReviews)
+	if !ok {
+		return
+	}
+
+	comments, err := h.repo.FindByInterview(interview.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// Create godoc
+// @Summary Comment on a review, optionally replying to a thread or anchoring to a line range
+func (h *CommentHandler) Create(c *gin.Context) {
+	access, interview, ok := h.findInterview(c, model.PermCommentReviews)
+	if !ok {
+		return
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评论内容不能为空"})
+		return
+	}
+
+	comment := &model.ReviewComment{
+		InterviewID: interview.ID,
+		OwnerID:     access.OwnerID,
+		AuthorID:    c.GetInt64("userID"),
+		Body:        body,
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.repo.FindByID(interview.ID, *req.ParentID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "parent comment not found"})
+			return
+		}
+		// 只有一层回复，回复的回复挂到同一条主评论下
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		} else {
+			comment.ParentID = &parent.ID
+		}
+	} else if req.LineStart != nil || req.LineEnd != nil {
+		start, end, err := lineRange(req.LineStart, req.LineEnd, interview.ReviewContent)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		comment.LineStart, comment.LineEnd = &start, &end
+	}
+
+	mentionIDs, err := h.resolveMentions(access.OwnerID, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.Create(comment, mentionIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondComment(c, http.StatusCreated, interview.ID, comment.ID)
+}
+
+// Update godoc
+// @Summary Edit a comment; only its author may edit
+func (h *CommentHandler) Update(c *gin.Context) {
+	access, interview, ok := h.findInterview(c, model.PermCommentReviews)
+	if !ok {
+		return
+	}
+	comment, ok := h.findComment(c, interview.ID)
+	if !ok {
+		return
+	}
+
+	if comment.AuthorID != c.GetInt64("userID") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只能修改自己的评论"})
+		return
+	}
+	if comment.DeletedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "评论已删除"})
+		return
+	}
+
+	var req model.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "评论内容不能为空"})
+		return
+	}
+
+	mentionIDs, err := h.resolveMentions(access.OwnerID, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateBody(comment.ID, body, mentionIDs, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.respondComment(c, http.StatusOK, interview.ID, comment.ID)
+}
+
+// Delete godoc
+// @Summary Delete a comment; the author or the review's owner may delete
+func (h *CommentHandler) Delete(c *gin.Context) {
+	access, interview, ok := h.findInterview(c, model.PermViewReviews)
+	if !ok {
+		return
+	}
+	comment, ok := h.findComment(c, interview.ID)
+	if !ok {
+		return
+	}
+
+	if comment.AuthorID != c.GetInt64("userID") && !access.IsOwner() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只能删除自己的评论"})
+		return
+	}
+
+	if err := h.repo.SoftDelete(comment.ID, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// MarkRead godoc
+// @Summary Mark all comments on an interview review as read by the current user
+func (h *CommentHandler) MarkRead(c *gin.Context) {
+	_, interview, ok := h.findInterview(c, model.PermViewReviews)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.MarkRead(c.GetInt64("userID"), interview.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "read"})
+}
+
+// Unread godoc
+// @Summary Unread comment counts per interview across the user's own reviews and coached workspaces
+func (h *CommentHandler) Unread(c *gin.Context) {
+	userID := c.GetInt64("userID")
+	memberships, err := h.workspaces.FindMemberships(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ownerIDs := []int64{userID}
+	workspaceOf := map[int64]int64{}
+	for _, m := range memberships {
+		if m.Status != model.MemberAccepted || !containsString(m.Permissions, model.PermViewReviews) {
+			continue
+		}
+		ownerIDs = append(ownerIDs, m.OwnerID)
+		workspaceOf[m.OwnerID] = m.WorkspaceID
+	}
+
+	counts, err := h.repo.CountUnread(userID, ownerIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total, mentions int64
+	for i := range counts {
+		counts[i].WorkspaceID = workspaceOf[counts[i].OwnerID]
+		total += counts[i].Unread
+		mentions += counts[i].Mentions
+	}
+	if counts == nil {
+		counts = []model.UnreadCommentCount{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"total": total, "mentions": mentions, "interviews": counts})
+}
+
+// findInterview 按 workspace_id 校验权限后查找路径中的面试
+func (h *CommentHandler) findInterview(c *gin.Context, permission string) (workspaceAccess, *model.Interview, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return workspaceAccess{}, nil, false
+	}
+
+	access, ok := resolveAccess(c, h.workspaces, permission)
+	if !ok {
+		return workspaceAccess{}, nil, false
+	}
+
+	interview, err := h.interviews.FindByID(access.OwnerID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
+		return workspaceAccess{}, nil, false
+	}
+	return access, interview, true
+}
+
+func (h *CommentHandler) findComment(c *gin.Context, interviewID int64) (*model.ReviewComment, bool) {
+	id, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment id"})
+		return nil, false
+	}
+
+	comment, err := h.repo.FindByID(interviewID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return comment, true
+}
+
+// resolveMentions 解析正文中的 @用户名，只保留求职者本人和已加入其工作区的成员
+func (h *CommentHandler) resolveMentions(ownerID int64, body string) ([]int64, error) {
+	matches := mentionPattern.FindAllStringSubmatch(body, -1)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	participants := map[string]int64{}
+	owner, err := h.users.FindByID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	participants[owner.Username] = owner.ID
+
+	ws, err := h.workspaces.FindByOwner(ownerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if ws != nil {
+		members, err := h.workspaces.FindMembers(ws.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if m.Status == model.MemberAccepted {
+				participants[m.Username] = m.UserID
+			}
+		}
+	}
+
+	var ids []int64
+	seen := map[int64]bool{}
+	for _, match := range matches {
+		if id, ok := participants[match[1]]; ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// respondComment 重新读取评论，连同作者和提及返回
+func (h *CommentHandler) respondComment(c *gin.Context, status int, interviewID, commentID int64) {
+	comments, err := h.repo.FindByInterview(interviewID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, comment := range comments {
+		if comment.ID == commentID {
+			c.JSON(status, comment)
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+}
+
+// lineRange 校验评论锚定的行区间落在复盘正文内，只给出一端时视为单行
+func lineRange(start, end *int, review string) (int, int, error) {
+	if start == nil {
+		start = end
+	}
+	if end == nil {
+		end = start
+	}
+	if *end < *start {
+		return 0, 0, errors.New("line_end must not be before line_start")
+	}
+	lines := strings.Count(review, "\n") + 1
+	if review == "" || *end > lines {
+		return 0, 0, errors.New("line range is outside the review")
+	}
+	return *start, *end, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,379 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/config"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+// maxComparedOffers 一次最多对比的申请数
+const maxComparedOffers = 10
+
+// ComparisonHandler offer 横向对比。评价维度和打分只能由求职者本人维护；
+// 对比接口支持 workspace_id 参数，教练有 view_pipeline 权限即可查看
+type ComparisonHandler struct {
+	repo       *repository.ComparisonRepository
+	apps       *repository.ApplicationRepository
+	workspaces *repository.WorkspaceRepository
+}
+
+func NewComparisonHandler() *ComparisonHandler {
+	return &ComparisonHandler{
+		repo:       repository.NewComparisonRepository(),
+		apps:       repository.NewApplicationRepository(),
+		workspaces: repository.NewWorkspaceRepository(),
+	}
+}
+
+func (h *ComparisonHandler) RegisterRoutes(r *gin.RouterGroup) {
+	offers := r.Group("/offers")
+	{
+		offers.GET("/compare", h.Compare)
+		offers.GET("/criteria", h.ListCriteria)
+		offers.POST("/criteria", h.CreateCriterion)
+		offers.PUT("/criteria/:id", h.UpdateCriterion)
+		offers.DELETE("/criteria/:id", h.DeleteCriterion)
+	}
+	r.GET("/applications/:id/scores", h.ListScores)
+	r.PUT("/applications/:id/scores", h.UpdateScores)
+}
+
+// Compare godoc
+// @Summary Compare applications side by side: compensation components, 1/2/4-year totals, city, deadline and weighted scores
+// @Param ids query string true "Comma-separated application IDs, at most 10"
+// @Param currency query string false "Currency to normalize amounts to, defaults to currency.base in config"
+// @Param workspace_id query int false "Compare a coached seeker's applications"
+func (h *ComparisonHandler) Compare(c *gin.Context) {
+	ids, err := parseIDList(c.Query("ids"))
+	if err != nil || len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma-separated list of application ids"})
+		return
+	}
+	if len(ids) > maxComparedOffers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "最多同时对比 " + strconv.Itoa(maxComparedOffers) + " 个申请"})
+		return
+	}
+
+	currency := strings.ToUpper(c.DefaultQuery("currency", config.AppConfig.Currency.Base))
+	if currency == "" {
+		currency = "CNY"
+	}
+
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	apps, err := h.apps.FindByIDs(access.OwnerID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(apps) != len(ids) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	criteria, err := h.repo.FindCriteria(access.OwnerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	scores, err := h.repo.FindScores(access.OwnerID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	scoresByApp := make(map[int64][]model.ScoreItem)
+	for _, s := range scores {
+		scoresByApp[s.ApplicationID] = append(scoresByApp[s.ApplicationID], model.ScoreItem{CriterionID: s.CriterionID, Score: s.Score})
+	}
+
+	result := model.OfferComparison{
+		Currency: currency,
+		Criteria: criteria,
+		Offers:   make([]model.OfferColumn, 0, len(apps)),
+	}
+	for _, app := range apps {
+		column := model.OfferColumn{
+			ApplicationID: app.ID,
+			CompanyName:   app.CompanyName,
+			JobTitle:      app.JobTitle,
+			CurrentStatus: app.CurrentStatus,
+			City:          app.City,
+			Scores:        scoresByApp[app.ID],
+			WeightedScore: weightedScore(criteria, scoresByApp[app.ID]),
+		}
+		if column.Scores == nil {
+			column.Scores = []model.ScoreItem{}
+		}
+		if app.Offer != nil {
+			column.OfferStatus = app.Offer.Status
+			column.ResponseDeadline = app.Offer.ExpiresAt
+		}
+		if app.Compensation != nil {
+			rate, ok := exchangeRate(app.Compensation.Currency, currency)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "汇率表中缺少 " + app.Compensation.Currency + " 或 " + currency})
+				return
+			}
+			column.OriginalCurrency = app.Compensation.Currency
+			column.Rate = rate
+			column.Compensation, column.Totals = app.Compensation.Convert(currency, rate).Compared()
+		}
+		result.Offers = append(result.Offers, column)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListCriteria godoc
+// @Summary List the user's weighted offer criteria
+func (h *ComparisonHandler) ListCriteria(c *gin.Context) {
+	criteria, err := h.repo.FindCriteria(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, criteria)
+}
+
+// CreateCriterion godoc
+// @Summary Add a weighted offer criterion
+func (h *ComparisonHandler) CreateCriterion(c *gin.Context) {
+	var req model.CriterionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	name := strings.TrimSpace(req.Name)
+	if h.repo.CriterionNameExists(userID, name, 0) {
+		c.JSON(http.StatusConflict, gin.H{"error": "评价维度已存在"})
+		return
+	}
+
+	criterion := &model.OfferCriterion{UserID: userID, Name: name, Weight: req.Weight}
+	if err := h.repo.CreateCriterion(criterion); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, criterion)
+}
+
+// UpdateCriterion godoc
+// @Summary Rename or reweight an offer criterion
+func (h *ComparisonHandler) UpdateCriterion(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req model.CriterionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	name := strings.TrimSpace(req.Name)
+	if h.repo.CriterionNameExists(userID, name, id) {
+		c.JSON(http.StatusConflict, gin.H{"error": "评价维度已存在"})
+		return
+	}
+
+	criterion := &model.OfferCriterion{ID: id, UserID: userID, Name: name, Weight: req.Weight}
+	if err := h.repo.UpdateCriterion(criterion); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "criterion not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, criterion)
+}
+
+// DeleteCriterion godoc
+// @Summary Delete an offer criterion together with its scores
+func (h *ComparisonHandler) DeleteCriterion(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.DeleteCriterion(c.GetInt64("userID"), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "criterion not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// ListScores godoc
+// @Summary List an application's scores on each criterion
+func (h *ComparisonHandler) ListScores(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if !h.apps.Exists(userID, id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	scores, err := h.repo.FindScores(userID, []int64{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scores)
+}
+
+// UpdateScores godoc
+// @Summary Replace an application's scores; criteria left out become unscored
+func (h *ComparisonHandler) UpdateScores(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req model.UpdateScoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if !h.apps.Exists(userID, id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	seen := make(map[int64]bool, len(req.Scores))
+	scores := make([]model.OfferScore, 0, len(req.Scores))
+	for _, item := range req.Scores {
+		if seen[item.CriterionID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duplicate criterion_id " + strconv.FormatInt(item.CriterionID, 10)})
+			return
+		}
+		seen[item.CriterionID] = true
+		scores = append(scores, model.OfferScore{
+			ApplicationID: id,
+			CriterionID:   item.CriterionID,
+			UserID:        userID,
+			Score:         item.Score,
+		})
+	}
+
+	if len(scores) > 0 {
+		criterionIDs := make([]int64, 0, len(seen))
+		for criterionID := range seen {
+			criterionIDs = append(criterionIDs, criterionID)
+		}
+		count, err := h.repo.CountCriteria(userID, criterionIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if count != int64(len(criterionIDs)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown criterion_id"})
+			return
+		}
+	}
+
+	if err := h.repo.ReplaceScores(userID, id, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, scores)
+}
+
+// weightedScore 按已打分维度的权重加权平均；没有任何打分或权重之和为 0 时返回 nil
+func weightedScore(criteria []model.OfferCriterion, scores []model.ScoreItem) *float64 {
+	weights := make(map[int64]float64, len(criteria))
+	for _, cr := range criteria {
+		weights[cr.ID] = cr.Weight
+	}
+
+	var sum, totalWeight float64
+	for _, s := range scores {
+		sum += s.Score * weights[s.CriterionID]
+		totalWeight += weights[s.CriterionID]
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	score := math.Round(sum/totalWeight*100) / 100
+	return &score
+}
+
+// exchangeRate 返回 1 单位 from 折合多少 to，汇率表以 currency.base 为基准
+func exchangeRate(from, to string) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	fromRate, ok := baseRate(from)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := baseRate(to)
+	if !ok {
+		return 0, false
+	}
+	return fromRate / toRate, true
+}
+
+func baseRate(currency string) (float64, bool) {
+	cfg := config.AppConfig.Currency
+	if currency == cfg.Base || (cfg.Base == "" && currency == "CNY") {
+		return 1, true
+	}
+	rate, ok := cfg.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+// parseIDList 解析逗号分隔的 ID 列表，忽略空项和重复项
+func parseIDList(value string) ([]int64, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
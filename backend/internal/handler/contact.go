@@ -0,0 +1,395 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+// ContactHandler 招聘方联系人：HR、猎头、面试官，以及与他们的联系记录。联系人只对求职者本人可见
+type ContactHandler struct {
+	repo *repository.ContactRepository
+}
+
+func NewContactHandler() *ContactHandler {
+	return &ContactHandler{repo: repository.NewContactRepository()}
+}
+
+func (h *ContactHandler) RegisterRoutes(r *gin.RouterGroup) {
+	contacts := r.Group("/contacts")
+	{
+		contacts.GET("", h.List)
+		contacts.POST("", h.Create)
+		contacts.GET("/follow-up", h.FollowUp)
+		contacts.GET("/:id", h.Get)
+		contacts.PUT("/:id", h.Update)
+		contacts.DELETE("/:id", h.Delete)
+		contacts.POST("/:id/applications/:application_id", h.LinkApplication)
+		contacts.DELETE("/:id/applications/:application_id", h.UnlinkApplication)
+		contacts.POST("/:id/interviews/:interview_id", h.LinkInterview)
+		contacts.DELETE("/:id/interviews/:interview_id", h.UnlinkInterview)
+		contacts.GET("/:id/interactions", h.ListInteractions)
+		contacts.POST("/:id/interactions", h.CreateInteraction)
+		contacts.DELETE("/:id/interactions/:interaction_id", h.DeleteInteraction)
+	}
+	r.GET("/applications/:id/contacts", h.ListByApplication)
+	r.GET("/interviews/:id/contacts", h.ListByInterview)
+}
+
+// List godoc
+// @Summary List contacts, optionally filtered by name, company or email
+// @Param keyword query string false "Keyword"
+func (h *ContactHandler) List(c *gin.Context) {
+	contacts, err := h.repo.FindAll(c.GetInt64("userID"), strings.TrimSpace(c.Query("keyword")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contacts)
+}
+
+// Get godoc
+// @Summary Get a contact with linked applications, interviews and the interaction log
+func (h *ContactHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	detail, err := h.repo.FindDetail(c.GetInt64("userID"), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// Create godoc
+// @Summary Create a contact
+func (h *ContactHandler) Create(c *gin.Context) {
+	var req model.ContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contact := &model.Contact{UserID: c.GetInt64("userID")}
+	applyContactRequest(contact, &req)
+	if contact.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	if err := h.repo.Create(contact); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, contact)
+}
+
+// Update godoc
+// @Summary Update a contact
+func (h *ContactHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req model.ContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contact, err := h.repo.FindByID(c.GetInt64("userID"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+		return
+	}
+
+	applyContactRequest(contact, &req)
+	if contact.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	if err := h.repo.Update(contact); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contact)
+}
+
+// Delete godoc
+// @Summary Delete a contact together with its links and interaction log
+func (h *ContactHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.Delete(c.GetInt64("userID"), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// FollowUp godoc
+// @Summary List contacts not touched in the last N days, least recently contacted first
+// @Param days query int false "Days since the last interaction, 1-365, default 14"
+func (h *ContactHandler) FollowUp(c *gin.Context) {
+	days := 14
+	if s := c.Query("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d < 1 || d > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+		days = d
+	}
+
+	contacts, err := h.repo.FindNeedingFollowUp(c.GetInt64("userID"), time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contacts)
+}
+
+// LinkApplication godoc
+// @Summary Link a contact to an application
+func (h *ContactHandler) LinkApplication(c *gin.Context) {
+	h.link(c, "application_id", "application not found", h.repo.LinkApplication)
+}
+
+// UnlinkApplication godoc
+// @Summary Unlink a contact from an application
+func (h *ContactHandler) UnlinkApplication(c *gin.Context) {
+	h.unlink(c, "application_id", h.repo.UnlinkApplication)
+}
+
+// LinkInterview godoc
+// @Summary Link a contact to an interview, e.g. as its interviewer
+func (h *ContactHandler) LinkInterview(c *gin.Context) {
+	h.link(c, "interview_id", "interview not found", h.repo.LinkInterview)
+}
+
+// UnlinkInterview godoc
+// @Summary Unlink a contact from an interview
+func (h *ContactHandler) UnlinkInterview(c *gin.Context) {
+	h.unlink(c, "interview_id", h.repo.UnlinkInterview)
+}
+
+// ListInteractions godoc
+// @Summary List the interaction log of a contact, newest first
+func (h *ContactHandler) ListInteractions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if _, err := h.repo.FindByID(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+		return
+	}
+
+	interactions, err := h.repo.FindInteractions(userID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, interactions)
+}
+
+// CreateInteraction godoc
+// @Summary Log an email, call, message or meeting with a contact
+func (h *ContactHandler) CreateInteraction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req model.CreateInteractionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if _, err := h.repo.FindByID(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+		return
+	}
+
+	interaction := &model.ContactInteraction{
+		ContactID:  id,
+		UserID:     userID,
+		Kind:       req.Kind,
+		Summary:    req.Summary,
+		OccurredAt: time.Now(),
+	}
+	if req.OccurredAt != nil {
+		interaction.OccurredAt = *req.OccurredAt
+	}
+	if err := h.repo.CreateInteraction(interaction); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, interaction)
+}
+
+// DeleteInteraction godoc
+// @Summary Delete an entry from a contact's interaction log
+func (h *ContactHandler) DeleteInteraction(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	interactionID, err := strconv.ParseInt(c.Param("interaction_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interaction id"})
+		return
+	}
+
+	if err := h.repo.DeleteInteraction(c.GetInt64("userID"), id, interactionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "interaction not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// ListByApplication godoc
+// @Summary List contacts linked to an application
+func (h *ContactHandler) ListByApplication(c *gin.Context) {
+	appID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	contacts, err := h.repo.FindByApplication(c.GetInt64("userID"), appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contacts)
+}
+
+// ListByInterview godoc
+// @Summary List contacts linked to an interview
+func (h *ContactHandler) ListByInterview(c *gin.Context) {
+	interviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	contacts, err := h.repo.FindByInterview(c.GetInt64("userID"), interviewID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contacts)
+}
+
+// link 解析联系人和目标 id 后调用关联函数，联系人和目标都必须属于当前用户
+func (h *ContactHandler) link(c *gin.Context, param, notFound string, fn func(userID, contactID, targetID int64) error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	targetID, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + strings.ReplaceAll(param, "_", " ")})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if _, err := h.repo.FindByID(userID, id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "contact not found"})
+		return
+	}
+
+	if err := fn(userID, id, targetID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "linked"})
+}
+
+func (h *ContactHandler) unlink(c *gin.Context, param string, fn func(userID, contactID, targetID int64) error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	targetID, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + strings.ReplaceAll(param, "_", " ")})
+		return
+	}
+
+	if err := fn(c.GetInt64("userID"), id, targetID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "link not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "unlinked"})
+}
+
+func applyContactRequest(contact *model.Contact, req *model.ContactRequest) {
+	contact.Name = strings.TrimSpace(req.Name)
+	contact.Role = strings.TrimSpace(req.Role)
+	contact.Company = strings.TrimSpace(req.Company)
+	contact.Email = strings.TrimSpace(req.Email)
+	contact.Phone = strings.TrimSpace(req.Phone)
+	contact.WeChat = strings.TrimSpace(req.WeChat)
+	contact.LinkedIn = strings.TrimSpace(req.LinkedIn)
+	contact.Notes = req.Notes
+}
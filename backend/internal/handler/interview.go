@@ -1,29 +1,49 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/config"
 	"offermatrix/internal/model"
 	"offermatrix/internal/repository"
+	"offermatrix/internal/webhook"
 )
 
+// InterviewHandler 的查询、新建和修改接口支持 workspace_id 参数，供教练访问求职者的面试；
+// 复盘正文和删除只能由求职者本人操作
 type InterviewHandler struct {
-	repo *repository.InterviewRepository
+	repo         *repository.InterviewRepository
+	appRepo      *repository.ApplicationRepository
+	reminderRepo *repository.ReminderRepository
+	workspaces   *repository.WorkspaceRepository
+	events       *webhook.Dispatcher
 }
 
 func NewInterviewHandler() *InterviewHandler {
-	return &InterviewHandler{repo: repository.NewInterviewRepository()}
+	return &InterviewHandler{
+		repo:         repository.NewInterviewRepository(),
+		appRepo:      repository.NewApplicationRepository(),
+		reminderRepo: repository.NewReminderRepository(),
+		workspaces:   repository.NewWorkspaceRepository(),
+		events:       webhook.NewDispatcher(),
+	}
 }
 
 func (h *InterviewHandler) RegisterRoutes(r *gin.RouterGroup) {
 	interviews := r.Group("/interviews")
 	{
 		interviews.GET("", h.List)
+		interviews.GET("/conflicts", h.Conflicts)
 		interviews.GET("/:id", h.Get)
 		interviews.POST("", h.Create)
+		interviews.POST("/import/preview", h.ImportPreview)
+		interviews.POST("/import/confirm", h.ImportConfirm)
 		interviews.PUT("/:id", h.Update)
 		interviews.PATCH("/:id/review", h.UpdateReview)
 		interviews.DELETE("/:id", h.Delete)
@@ -34,7 +54,14 @@ func (h *InterviewHandler) RegisterRoutes(r *gin.RouterGroup) {
 // @Summary List all interviews
 // @Param start query string false "Start time (RFC3339)"
 // @Param end query string false "End time (RFC3339)"
+// @Param workspace_id query int false "List a coached seeker's interviews"
 func (h *InterviewHandler) List(c *gin.Context) {
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	userID := access.OwnerID
 	startStr := c.Query("start")
 	endStr := c.Query("end")
 
@@ -42,28 +69,15 @@ func (h *InterviewHandler) List(c *gin.Context) {
 	var err error
 
 	if startStr != "" && endStr != "" {
-		start, parseErr := time.Parse(time.RFC3339, startStr)
+		start, end, parseErr := parseTimeRange(startStr, endStr)
 		if parseErr != nil {
-			start, parseErr = time.Parse("2006-01-02", startStr)
-			if parseErr != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start time format"})
-				return
-			}
-		}
-
-		end, parseErr := time.Parse(time.RFC3339, endStr)
-		if parseErr != nil {
-			end, parseErr = time.Parse("2006-01-02", endStr)
-			if parseErr != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end time format"})
-				return
-			}
-			end = end.Add(24*time.Hour - time.Second)
+			c.JSON(http.StatusBadRequest, gin.H{"error": parseErr.Error()})
+			return
 		}
 
-		interviews, err = h.repo.FindByTimeRange(start, end)
+		interviews, err = h.repo.FindByTimeRange(userID, start, end)
 	} else {
-		interviews, err = h.repo.FindAll()
+		interviews, err = h.repo.FindAll(userID)
 	}
 
 	if err != nil {
@@ -71,6 +85,7 @@ func (h *InterviewHandler) List(c *gin.Context) {
 		return
 	}
 
+	access.redactReviews(interviews)
 	c.JSON(http.StatusOK, interviews)
 }
 
@@ -83,12 +98,18 @@ func (h *InterviewHandler) Get(c *gin.Context) {
 		return
 	}
 
-	interview, err := h.repo.FindByID(id)
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	interview, err := h.repo.FindByID(access.OwnerID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
 		return
 	}
 
+	access.redactReview(interview)
 	c.JSON(http.StatusOK, interview)
 }
 
@@ -101,6 +122,21 @@ func (h *InterviewHandler) Create(c *gin.Context) {
 		return
 	}
 
+	access, ok := resolveAccess(c, h.workspaces, model.PermEditPipeline)
+	if !ok {
+		return
+	}
+	if req.ReviewContent != "" && !access.IsOwner() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "面试复盘只能由本人编写"})
+		return
+	}
+
+	userID := access.OwnerID
+	if !h.appRepo.Exists(userID, req.ApplicationID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
 	startTime, err := time.Parse(time.RFC3339, req.StartTime)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time format, use RFC3339"})
@@ -113,7 +149,13 @@ func (h *InterviewHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if !endTime.After(startTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be after start_time"})
+		return
+	}
+
 	interview := &model.Interview{
+		UserID:        userID,
 		ApplicationID: req.ApplicationID,
 		RoundName:     req.RoundName,
 		StartTime:     startTime,
@@ -124,7 +166,11 @@ func (h *InterviewHandler) Create(c *gin.Context) {
 	}
 
 	if interview.Status == "" {
-		interview.Status = "SCHEDULED"
+		interview.Status = model.InterviewStatusScheduled
+	}
+
+	if !h.checkConflicts(c, access, interview) {
+		return
 	}
 
 	if err := h.repo.Create(interview); err != nil {
@@ -132,6 +178,10 @@ func (h *InterviewHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if interview.Status == model.InterviewStatusScheduled {
+		h.events.Emit(userID, model.EventInterviewScheduled, interview)
+	}
+
 	c.JSON(http.StatusCreated, interview)
 }
 
@@ -144,7 +194,12 @@ func (h *InterviewHandler) Update(c *gin.Context) {
 		return
 	}
 
-	interview, err := h.repo.FindByID(id)
+	access, ok := resolveAccess(c, h.workspaces, model.PermEditPipeline)
+	if !ok {
+		return
+	}
+
+	interview, err := h.repo.FindByID(access.OwnerID, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
 		return
@@ -155,7 +210,12 @@ func (h *InterviewHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.ReviewContent != "" && !access.IsOwner() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "面试复盘只能由本人编写"})
+		return
+	}
 
+	before := *interview
 	if req.RoundName != "" {
 		interview.RoundName = req.RoundName
 	}
@@ -185,14 +245,66 @@ func (h *InterviewHandler) Update(c *gin.Context) {
 		interview.ReviewContent = req.ReviewContent
 	}
 
+	if !interview.EndTime.After(interview.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be after start_time"})
+		return
+	}
+	timeChanged := !interview.StartTime.Equal(before.StartTime) || !interview.EndTime.Equal(before.EndTime)
+	rescheduled := timeChanged || interview.Status != before.Status
+	if rescheduled && !h.checkConflicts(c, access, interview) {
+		return
+	}
+
 	if err := h.repo.Update(interview); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 改期或取消后丢弃旧时间的待发提醒，调度器会按新时间重新生成
+	if rescheduled {
+		if err := h.reminderRepo.DeletePendingByInterview(interview.ID); err != nil {
+			log.Printf("Failed to reset reminders for interview %d: %v", interview.ID, err)
+		}
+	}
+
+	switch {
+	case interview.Status == model.InterviewStatusCancelled && before.Status != model.InterviewStatusCancelled:
+		h.events.Emit(interview.UserID, model.EventInterviewCancelled, interview)
+	case interview.Status == model.InterviewStatusScheduled && before.Status != model.InterviewStatusScheduled:
+		h.events.Emit(interview.UserID, model.EventInterviewScheduled, interview)
+	case timeChanged && interview.Status == model.InterviewStatusScheduled:
+		h.events.Emit(interview.UserID, model.EventInterviewRescheduled, gin.H{
+			"interview":           interview,
+			"previous_start_time": before.StartTime,
+			"previous_end_time":   before.EndTime,
+		})
+	}
+	if interview.ReviewContent != before.ReviewContent && interview.ReviewContent != "" {
+		h.events.Emit(interview.UserID, model.EventInterviewReviewWritten, interview)
+	}
+
+	access.redactReview(interview)
 	c.JSON(http.StatusOK, interview)
 }
 
+// Conflicts godoc
+// @Summary Report overlapping SCHEDULED interviews of the current user
+func (h *InterviewHandler) Conflicts(c *gin.Context) {
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	interviews, err := h.repo.FindScheduled(access.OwnerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	access.redactReviews(interviews)
+
+	c.JSON(http.StatusOK, findConflicts(interviews, conflictBuffer()))
+}
+
 // UpdateReview godoc
 // @Summary Update interview review content
 func (h *InterviewHandler) UpdateReview(c *gin.Context) {
@@ -208,12 +320,20 @@ func (h *InterviewHandler) UpdateReview(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.UpdateReview(id, req.ReviewContent); err != nil {
+	userID := c.GetInt64("userID")
+	if err := h.repo.UpdateReview(userID, id, req.ReviewContent); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	interview, _ := h.repo.FindByID(id)
+	interview, _ := h.repo.FindByID(userID, id)
+	if interview != nil && req.ReviewContent != "" {
+		h.events.Emit(userID, model.EventInterviewReviewWritten, interview)
+	}
 	c.JSON(http.StatusOK, interview)
 }
 
@@ -226,10 +346,118 @@ func (h *InterviewHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(id); err != nil {
+	userID := c.GetInt64("userID")
+	interview, err := h.repo.FindByID(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
+		return
+	}
+
+	if err := h.repo.Delete(userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "interview not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// 删除待进行的面试对订阅方而言等同于取消
+	if interview.Status == model.InterviewStatusScheduled {
+		h.events.Emit(userID, model.EventInterviewCancelled, interview)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
+
+// checkConflicts 校验待进行面试是否与其他面试冲突，冲突时写入 409 响应并返回 false。
+// 请求带 force=true 时跳过检测。
+func (h *InterviewHandler) checkConflicts(c *gin.Context, access workspaceAccess, interview *model.Interview) bool {
+	if interview.Status != model.InterviewStatusScheduled || c.Query("force") == "true" {
+		return true
+	}
+
+	buffer := conflictBuffer()
+	conflicts, err := h.repo.FindOverlapping(interview.UserID,
+		interview.StartTime.Add(-buffer), interview.EndTime.Add(buffer), interview.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if len(conflicts) > 0 {
+		access.redactReviews(conflicts)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "interview time conflicts with other scheduled interviews, retry with force=true to save anyway",
+			"conflicts": conflicts,
+		})
+		return false
+	}
+	return true
+}
+
+func conflictBuffer() time.Duration {
+	return time.Duration(config.AppConfig.Interview.ConflictBufferMinutes) * time.Minute
+}
+
+// findConflicts 找出相互重叠的面试，interviews 需按 start_time 升序排列
+func findConflicts(interviews []model.Interview, buffer time.Duration) []model.InterviewConflict {
+	overlaps := make(map[int][]model.Interview)
+	for i := range interviews {
+		for j := i + 1; j < len(interviews); j++ {
+			if !interviews[j].StartTime.Before(interviews[i].EndTime.Add(buffer)) {
+				break
+			}
+			if timesOverlap(interviews[i], interviews[j], buffer) {
+				overlaps[i] = append(overlaps[i], interviews[j])
+				overlaps[j] = append(overlaps[j], interviews[i])
+			}
+		}
+	}
+
+	result := []model.InterviewConflict{}
+	for i := range interviews {
+		if len(overlaps[i]) > 0 {
+			result = append(result, model.InterviewConflict{
+				Interview:     interviews[i],
+				ConflictsWith: overlaps[i],
+			})
+		}
+	}
+	return result
+}
+
+// timesOverlap 判断两场面试加上前后缓冲后是否重叠，恰好间隔 buffer 的前后两场不算冲突
+func timesOverlap(a, b model.Interview, buffer time.Duration) bool {
+	return a.StartTime.Before(b.EndTime.Add(buffer)) && b.StartTime.Before(a.EndTime.Add(buffer))
+}
+
+// parseTimeRange 解析 RFC3339 或 2006-01-02 格式的时间范围，纯日期的 end 取当天结束
+func parseTimeRange(startStr, endStr string) (time.Time, time.Time, error) {
+	start, err := parseTimeBound(startStr, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start time format")
+	}
+
+	end, err := parseTimeBound(endStr, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end time format")
+	}
+
+	return start, end, nil
+}
+
+// parseTimeBound 接受 RFC3339 或纯日期；纯日期作为结束时间时取当天最后一秒
+func parseTimeBound(value string, isEnd bool) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+	t, err = time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if isEnd {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t, nil
+}
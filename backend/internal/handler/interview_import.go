@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/ical"
+)
+
+const maxInvitationSize = 1 << 20
+
+// defaultImportDuration 邀请没有 DTEND 和 DURATION 时假定的面试时长
+const defaultImportDuration = time.Hour
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'\\]+`)
+
+// meetingHosts 常见会议平台域名，提取会议链接时优先匹配
+var meetingHosts = []string{
+	"meeting.tencent.com", "voovmeeting.com", "zoom.us", "zoom.com.cn",
+	"feishu.cn", "larksuite.com", "dingtalk.com", "welink.huaweicloud.com",
+	"teams.microsoft.com", "teams.live.com", "meet.google.com", "webex.com",
+}
+
+// genericMailDomains 公共邮箱域名无法反映公司，不参与匹配
+var genericMailDomains = map[string]bool{
+	"gmail.com": true, "qq.com": true, "163.com": true, "126.com": true,
+	"outlook.com": true, "hotmail.com": true, "foxmail.com": true,
+	"icloud.com": true, "yahoo.com": true, "sina.com": true,
+}
+
+// ImportPreview godoc
+// @Summary Parse an uploaded .ics invitation and preview the interviews it contains
+// @Param file formData file false ".ics file, the raw request body is used when absent"
+func (h *InterviewHandler) ImportPreview(c *gin.Context) {
+	data, err := readInvitation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := ical.ParseEvents(strings.NewReader(string(data)), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	apps, err := h.appRepo.FindAll(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	buffer := conflictBuffer()
+	previews := []model.ImportedInterview{}
+	for _, event := range events {
+		if event.Status == "CANCELLED" {
+			continue
+		}
+		preview := buildImportPreview(event, apps)
+		preview.Conflicts, err = h.repo.FindOverlapping(userID,
+			preview.StartTime.Add(-buffer), preview.EndTime.Add(buffer), 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		previews = append(previews, preview)
+	}
+
+	c.JSON(http.StatusOK, previews)
+}
+
+// ImportConfirm godoc
+// @Summary Create interviews from a confirmed import preview in a single transaction
+// @Param force query bool false "Save even if the interviews conflict with scheduled ones"
+func (h *InterviewHandler) ImportConfirm(c *gin.Context) {
+	var req model.ConfirmImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	owner := workspaceAccess{OwnerID: userID}
+	force := c.Query("force") == "true"
+	buffer := conflictBuffer()
+	interviews := make([]*model.Interview, 0, len(req.Items))
+	companies := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		startTime, err := time.Parse(time.RFC3339, item.StartTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time format, use RFC3339"})
+			return
+		}
+		endTime, err := time.Parse(time.RFC3339, item.EndTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time format, use RFC3339"})
+			return
+		}
+		if !endTime.After(startTime) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end_time must be after start_time"})
+			return
+		}
+		if item.ApplicationID == 0 && strings.TrimSpace(item.CompanyName) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "company_name is required when application_id is empty"})
+			return
+		}
+		if item.ApplicationID != 0 && !h.appRepo.Exists(userID, item.ApplicationID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+			return
+		}
+
+		interview := &model.Interview{
+			UserID:        userID,
+			ApplicationID: item.ApplicationID,
+			RoundName:     item.RoundName,
+			StartTime:     startTime,
+			EndTime:       endTime,
+			Status:        model.InterviewStatusScheduled,
+			MeetingLink:   item.MeetingLink,
+			Notes:         item.Notes,
+		}
+		if !h.checkConflicts(c, owner, interview) {
+			return
+		}
+		// 同一批导入的面试之间也要检查，数据库里还没有它们
+		if conflicts := batchConflicts(interviews, interview, buffer); len(conflicts) > 0 && !force {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "interviews in this import overlap each other, retry with force=true to save anyway",
+				"conflicts": conflicts,
+			})
+			return
+		}
+		interviews = append(interviews, interview)
+
+		company := ""
+		if item.ApplicationID == 0 {
+			company = strings.TrimSpace(item.CompanyName)
+		}
+		companies = append(companies, company)
+	}
+
+	// 全部写入或全部不写入，失败后重试不会产生重复的面试
+	apps, err := h.repo.CreateImported(interviews, companies)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, app := range apps {
+		h.events.Emit(userID, model.EventApplicationCreated, app)
+	}
+	for _, interview := range interviews {
+		h.events.Emit(userID, model.EventInterviewScheduled, interview)
+	}
+
+	c.JSON(http.StatusCreated, interviews)
+}
+
+func readInvitation(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxInvitationSize)
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+	return io.ReadAll(c.Request.Body)
+}
+
+// batchConflicts 返回 accepted 中与 interview 时间重叠的面试
+func batchConflicts(accepted []*model.Interview, interview *model.Interview, buffer time.Duration) []model.Interview {
+	conflicts := []model.Interview{}
+	for _, other := range accepted {
+		if timesOverlap(*other, *interview, buffer) {
+			conflicts = append(conflicts, *other)
+		}
+	}
+	return conflicts
+}
+
+func buildImportPreview(event ical.ParsedEvent, apps []model.Application) model.ImportedInterview {
+	preview := model.ImportedInterview{
+		UID:       event.UID,
+		StartTime: event.Start,
+		EndTime:   event.End,
+		Location:  event.Location,
+		Organizer: event.Organizer,
+		Notes:     strings.TrimSpace(event.Description),
+	}
+	// 没写结束时间的邀请按默认时长处理，否则确认导入时会因结束时间不晚于开始时间被拒绝
+	if !preview.EndTime.After(preview.StartTime) {
+		preview.EndTime = preview.StartTime.Add(defaultImportDuration)
+	}
+	preview.MeetingLink = extractMeetingLink(event.URL, event.Location, event.Description)
+
+	domainLabel := organizerDomainLabel(event.Organizer)
+	if app := matchByDomain(apps, domainLabel); app != nil {
+		preview.ApplicationID, preview.CompanyName, preview.MatchedBy = app.ID, app.CompanyName, "domain"
+	} else if app := matchByCompany(apps, event.Summary+"\n"+event.OrganizerName+"\n"+event.Description); app != nil {
+		preview.ApplicationID, preview.CompanyName, preview.MatchedBy = app.ID, app.CompanyName, "company"
+	} else if domainLabel != "" {
+		preview.CompanyName = strings.ToUpper(domainLabel[:1]) + domainLabel[1:]
+	}
+
+	preview.RoundName = guessRoundName(event.Summary, preview.CompanyName)
+	return preview
+}
+
+// extractMeetingLink 从各字段中提取会议链接，会议平台链接优先
+func extractMeetingLink(fields ...string) string {
+	var first string
+	for _, field := range fields {
+		for _, link := range urlPattern.FindAllString(field, -1) {
+			link = strings.TrimRight(link, ".,;)>")
+			for _, host := range meetingHosts {
+				if strings.Contains(link, host) {
+					return link
+				}
+			}
+			if first == "" {
+				first = link
+			}
+		}
+	}
+	return first
+}
+
+// organizerDomainLabel 取组织者邮箱的主域名，例如 hr@mail.bytedance.com -> bytedance
+func organizerDomainLabel(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	domain := strings.ToLower(email[at+1:])
+	if genericMailDomains[domain] {
+		return ""
+	}
+
+	labels := strings.Split(domain, ".")
+	for len(labels) > 1 {
+		last := labels[len(labels)-1]
+		if last == "com" || last == "cn" || last == "net" || last == "org" || last == "io" || last == "co" || len(last) == 2 {
+			labels = labels[:len(labels)-1]
+			continue
+		}
+		break
+	}
+	return labels[len(labels)-1]
+}
+
+func matchByDomain(apps []model.Application, label string) *model.Application {
+	if label == "" {
+		return nil
+	}
+	for i := range apps {
+		name := strings.ToLower(strings.ReplaceAll(apps[i].CompanyName, " ", ""))
+		if name != "" && (strings.Contains(name, label) || strings.Contains(label, name)) {
+			return &apps[i]
+		}
+	}
+	return nil
+}
+
+// matchByCompany 在邀请文本中查找已有申请的公司名，优先匹配更长的名称
+func matchByCompany(apps []model.Application, text string) *model.Application {
+	text = strings.ToLower(text)
+	var best *model.Application
+	for i := range apps {
+		name := strings.ToLower(strings.TrimSpace(apps[i].CompanyName))
+		if name == "" || !strings.Contains(text, name) {
+			continue
+		}
+		if best == nil || len(name) > len(best.CompanyName) {
+			best = &apps[i]
+		}
+	}
+	return best
+}
+
+func guessRoundName(summary, company string) string {
+	round := summary
+	if company != "" {
+		round = strings.ReplaceAll(round, company, "")
+	}
+	round = strings.Trim(round, " -_|·:：,，【】[]()（）")
+	if round == "" {
+		return "面试"
+	}
+	return round
+}
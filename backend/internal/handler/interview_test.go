@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"offermatrix/internal/model"
+)
+
+func testInterview(id int64, start string, minutes int) model.Interview {
+	t, err := time.Parse("15:04", start)
+	if err != nil {
+		panic(err)
+	}
+	s := time.Date(2026, 2, 3, t.Hour(), t.Minute(), 0, 0, time.UTC)
+	return model.Interview{ID: id, StartTime: s, EndTime: s.Add(time.Duration(minutes) * time.Minute)}
+}
+
+func TestFindConflicts(t *testing.T) {
+	buffer := 15 * time.Minute
+	tests := []struct {
+		name       string
+		interviews []model.Interview
+		buffer     time.Duration
+		want       map[int64][]int64
+	}{
+		{
+			name:       "overlapping",
+			interviews: []model.Interview{testInterview(1, "10:00", 60), testInterview(2, "10:30", 60)},
+			buffer:     buffer,
+			want:       map[int64][]int64{1: {2}, 2: {1}},
+		},
+		{
+			name:       "back to back without buffer",
+			interviews: []model.Interview{testInterview(1, "10:00", 60), testInterview(2, "11:00", 60)},
+			want:       map[int64][]int64{},
+		},
+		{
+			name:       "back to back within buffer",
+			interviews: []model.Interview{testInterview(1, "10:00", 60), testInterview(2, "11:00", 60)},
+			buffer:     buffer,
+			want:       map[int64][]int64{1: {2}, 2: {1}},
+		},
+		{
+			name:       "gap exactly the buffer",
+			interviews: []model.Interview{testInterview(1, "10:00", 60), testInterview(2, "11:15", 60)},
+			buffer:     buffer,
+			want:       map[int64][]int64{},
+		},
+		{
+			name:       "gap one minute short of the buffer",
+			interviews: []model.Interview{testInterview(1, "10:00", 60), testInterview(2, "11:14", 60)},
+			buffer:     buffer,
+			want:       map[int64][]int64{1: {2}, 2: {1}},
+		},
+		{
+			// 长面试覆盖后面多场，后面两场之间不冲突
+			name: "long interview spans several",
+			interviews: []model.Interview{
+				testInterview(1, "09:00", 240),
+				testInterview(2, "10:00", 30),
+				testInterview(3, "11:00", 30),
+				testInterview(4, "14:00", 30),
+			},
+			want: map[int64][]int64{1: {2, 3}, 2: {1}, 3: {1}},
+		},
+		{
+			name:       "single",
+			interviews: []model.Interview{testInterview(1, "10:00", 60)},
+			buffer:     buffer,
+			want:       map[int64][]int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findConflicts(tt.interviews, tt.buffer)
+			if got == nil {
+				t.Fatal("findConflicts returned nil, want an empty slice for JSON")
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d conflicting interviews, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for _, c := range got {
+				want := tt.want[c.Interview.ID]
+				if len(c.ConflictsWith) != len(want) {
+					t.Fatalf("interview %d conflicts with %d, want %v", c.Interview.ID, len(c.ConflictsWith), want)
+				}
+				for i, other := range c.ConflictsWith {
+					if other.ID != want[i] {
+						t.Errorf("interview %d conflicts with %d, want %d", c.Interview.ID, other.ID, want[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"offermatrix/pkg/jwt"
+)
+
+// JWKS godoc
+// @Summary Public keys for verifying access tokens, selected by the kid header
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwt.PublicJWKS())
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+// OfferHandler 申请收到的 offer、答复截止时间和谈判记录。查询接口支持 workspace_id 参数，
+// 教练有 view_pipeline 权限即可查看；修改涉及薪资谈判，只能由求职者本人操作
+type OfferHandler struct {
+	repo         *repository.OfferRepository
+	apps         *repository.ApplicationRepository
+	reminderRepo *repository.ReminderRepository
+	workspaces   *repository.WorkspaceRepository
+}
+
+func NewOfferHandler() *OfferHandler {
+	return &OfferHandler{
+		repo:         repository.NewOfferRepository(),
+		apps:         repository.NewApplicationRepository(),
+		reminderRepo: repository.NewReminderRepository(),
+		workspaces:   repository.NewWorkspaceRepository(),
+	}
+}
+
+func (h *OfferHandler) RegisterRoutes(r *gin.RouterGroup) {
+	offer := r.Group("/applications/:id/offer")
+	{
+		offer.GET("", h.Get)
+		offer.PUT("", h.Save)
+		offer.DELETE("", h.Delete)
+		offer.POST("/negotiations", h.CreateNegotiation)
+		offer.DELETE("/negotiations/:negotiation_id", h.DeleteNegotiation)
+	}
+	r.GET("/offers/expiring", h.Expiring)
+}
+
+// Get godoc
+// @Summary Get the offer of an application with its negotiation log
+// @Param workspace_id query int false "Coached seeker's workspace"
+func (h *OfferHandler) Get(c *gin.Context) {
+	appID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	offer, err := h.repo.FindByApplication(access.OwnerID, appID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "offer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// Save godoc
+// @Summary Record or update the offer of an application: received date, response deadline and status
+func (h *OfferHandler) Save(c *gin.Context) {
+	appID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req model.SaveOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Status != "" && !model.IsValidOfferStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown offer status " + req.Status})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if !h.apps.Exists(userID, appID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		return
+	}
+
+	offer, err := h.repo.FindByApplication(userID, appID)
+	created := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !created {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if created {
+		now := time.Now()
+		offer = &model.Offer{
+			ApplicationID: appID,
+			UserID:        userID,
+			Status:        model.OfferStatusPending,
+			ReceivedAt:    &now,
+		}
+	}
+
+	previousStatus, previousExpiry := offer.Status, offer.ExpiresAt
+	if req.ReceivedAt != nil {
+		offer.ReceivedAt = req.ReceivedAt
+	}
+	if req.ExpiresAt != nil {
+		offer.ExpiresAt = req.ExpiresAt
+	}
+	if req.Notes != nil {
+		offer.Notes = *req.Notes
+	}
+	if req.Status != "" && req.Status != offer.Status {
+		offer.Status = req.Status
+		if req.Status == model.OfferStatusPending {
+			offer.DecidedAt = nil
+		} else {
+			now := time.Now()
+			offer.DecidedAt = &now
+		}
+	}
+	if offer.ReceivedAt != nil && offer.ExpiresAt != nil && offer.ExpiresAt.Before(*offer.ReceivedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must not be before received_at"})
+		return
+	}
+
+	if created {
+		err = h.repo.Create(offer)
+	} else {
+		err = h.repo.Update(offer)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 截止时间修改或已答复后丢弃旧的待发提醒，调度器会按新截止时间重新生成
+	if !created && (offer.Status != previousStatus || !sameTime(offer.ExpiresAt, previousExpiry)) {
+		if err := h.reminderRepo.DeletePendingByOffer(offer.ID); err != nil {
+			log.Printf("Failed to reset reminders for offer %d: %v", offer.ID, err)
+		}
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, offer)
+}
+
+// Delete godoc
+// @Summary Delete the offer of an application together with its negotiation log
+func (h *OfferHandler) Delete(c *gin.Context) {
+	appID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.Delete(c.GetInt64("userID"), appID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "offer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// CreateNegotiation godoc
+// @Summary Append an ask or a counter to the offer's negotiation log
+func (h *OfferHandler) CreateNegotiation(c *gin.Context) {
+	appID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req model.CreateNegotiationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	offer, err := h.repo.FindByApplication(userID, appID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "offer not found"})
+		return
+	}
+
+	negotiation := &model.OfferNegotiation{
+		OfferID:    offer.ID,
+		UserID:     userID,
+		Kind:       req.Kind,
+		Terms:      req.Terms,
+		Note:       req.Note,
+		OccurredAt: time.Now(),
+	}
+	if req.OccurredAt != nil {
+		negotiation.OccurredAt = *req.OccurredAt
+	}
+	if err := h.repo.CreateNegotiation(negotiation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	negotiation.ParseTerms()
+
+	c.JSON(http.StatusCreated, negotiation)
+}
+
+// DeleteNegotiation godoc
+// @Summary Delete an entry from the offer's negotiation log
+func (h *OfferHandler) DeleteNegotiation(c *gin.Context) {
+	appID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	negotiationID, err := strconv.ParseInt(c.Param("negotiation_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid negotiation id"})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	offer, err := h.repo.FindByApplication(userID, appID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "offer not found"})
+		return
+	}
+
+	if err := h.repo.DeleteNegotiation(userID, offer.ID, negotiationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "negotiation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// Expiring godoc
+// @Summary List pending offers whose response deadline falls within the next N days
+// @Param days query int false "Look-ahead window in days, 1-90, default 7"
+// @Param workspace_id query int false "Coached seeker's workspace"
+func (h *OfferHandler) Expiring(c *gin.Context) {
+	days := 7
+	if s := c.Query("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d < 1 || d > 90 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 90"})
+			return
+		}
+		days = d
+	}
+
+	access, ok := resolveAccess(c, h.workspaces, model.PermViewPipeline)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	offers, err := h.repo.FindExpiring(access.OwnerID, now, now.AddDate(0, 0, days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
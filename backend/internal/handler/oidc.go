@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"offermatrix/internal/config"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/oidc"
+	"offermatrix/pkg/securetoken"
+)
+
+const (
+	oidcStateCookie  = "om_oidc_state"
+	oidcCookiePath   = "/api/auth/oidc"
+	oidcStateTTL     = 10 * time.Minute
+	oidcHandoffTTL   = time.Minute
+	oidcHandoffToken = "omh_"
+)
+
+var (
+	errOIDCSignupDisabled = errors.New("该企业账号尚未开通，请联系管理员")
+	usernameUnsafeChars   = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+)
+
+// OIDCConfig godoc
+// @Summary Whether single sign-on is enabled, for the login page
+func (h *AuthHandler) OIDCConfig(c *gin.Context) {
+	cfg := config.AppConfig.OIDC
+	text := cfg.ButtonText
+	if text == "" {
+		text = "企业账号登录"
+	}
+	c.JSON(http.StatusOK, model.OIDCConfigResponse{Enabled: cfg.Enabled, ButtonText: text})
+}
+
+// OIDCLogin godoc
+// @Summary Start single sign-on: redirect to the identity provider with state, nonce and PKCE
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	if !config.AppConfig.OIDC.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "未启用单点登录"})
+		return
+	}
+
+	provider, err := h.oidcProvider(c)
+	if err != nil {
+		log.Printf("Failed to discover OIDC provider: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "无法连接身份提供方"})
+		return
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	if err := h.oidcStates.DeleteExpired(now); err != nil {
+		log.Printf("Failed to clean up OIDC login states: %v", err)
+	}
+	if err := h.oidcStates.Create(&model.OIDCLoginState{
+		StateHash:    securetoken.Hash(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		RedirectPath: safeRedirectPath(c.Query("redirect")),
+		ExpiresAt:    now.Add(oidcStateTTL),
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// state 同时写入 Cookie，回调时比对，防止把别人发起的登录回调塞给当前浏览器
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, int(oidcStateTTL.Seconds()), oidcCookiePath, "", isHTTPS(c), true)
+	c.Redirect(http.StatusFound, provider.AuthCodeURL(state, nonce, challenge))
+}
+
+// OIDCCallback godoc
+// @Summary Identity provider redirect target: verify the ID token and hand a one-time code to the frontend
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	base := oidcFrontendBase()
+	fail := func(msg string) {
+		c.Redirect(http.StatusFound, base+"/login?oidc_error="+url.QueryEscape(msg))
+	}
+
+	if !config.AppConfig.OIDC.Enabled {
+		fail("未启用单点登录")
+		return
+	}
+	if e := c.Query("error"); e != "" {
+		fail("身份提供方拒绝了登录：" + e)
+		return
+	}
+
+	state := c.Query("state")
+	cookie, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, oidcCookiePath, "", isHTTPS(c), true)
+	if state == "" || cookie != state {
+		fail("登录状态无效，请重新登录")
+		return
+	}
+
+	now := time.Now()
+	loginState, err := h.oidcStates.ConsumeState(securetoken.Hash(state), now)
+	if err != nil {
+		fail("登录已过期，请重新登录")
+		return
+	}
+
+	provider, err := h.oidcProvider(c)
+	if err != nil {
+		log.Printf("Failed to discover OIDC provider: %v", err)
+		fail("无法连接身份提供方")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	rawIDToken, err := provider.Exchange(ctx, c.Query("code"), loginState.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC code exchange failed: %v", err)
+		fail("身份验证失败")
+		return
+	}
+	idToken, err := provider.Verify(ctx, rawIDToken, loginState.Nonce)
+	if err != nil {
+		log.Printf("OIDC id_token rejected: %v", err)
+		fail("身份验证失败")
+		return
+	}
+
+	user, err := h.resolveOIDCUser(idToken)
+	if err != nil {
+		if errors.Is(err, errOIDCSignupDisabled) {
+			fail(err.Error())
+			return
+		}
+		log.Printf("Failed to resolve OIDC user %q: %v", idToken.Subject, err)
+		fail("登录失败")
+		return
+	}
+
+	handoff, hash, err := securetoken.Generate(oidcHandoffToken)
+	if err != nil {
+		fail("登录失败")
+		return
+	}
+	if err := h.oidcStates.SetHandoff(loginState.ID, user.ID, hash, now.Add(oidcHandoffTTL)); err != nil {
+		log.Printf("Failed to store OIDC handoff: %v", err)
+		fail("登录失败")
+		return
+	}
+
+	q := url.Values{}
+	q.Set("code", handoff)
+	q.Set("redirect", loginState.RedirectPath)
+	c.Redirect(http.StatusFound, base+"/oidc/callback?"+q.Encode())
+}
+
+// OIDCExchange godoc
+// @Summary Exchange the one-time code from OIDCCallback for access and refresh tokens
+func (h *AuthHandler) OIDCExchange(c *gin.Context) {
+	var req model.OIDCExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := h.oidcStates.ConsumeHandoff(securetoken.Hash(req.Code), time.Now())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "登录已过期，请重新登录"})
+		return
+	}
+
+	user, err := h.repo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+// oidcFrontendBase 返回登录完成后跳转的前端地址：优先 server.base_url，
+// 只配置了 oidc.redirect_url 时取其 origin，两者都不会来自请求的 Host
+func oidcFrontendBase() string {
+	if base := publicBaseURL(); base != "" {
+		return base
+	}
+	u, err := url.Parse(config.AppConfig.OIDC.RedirectURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// oidcProvider 首次使用时完成服务发现并缓存，身份提供方暂时不可用不影响服务启动
+func (h *AuthHandler) oidcProvider(c *gin.Context) (*oidc.Provider, error) {
+	h.oidcMu.Lock()
+	defer h.oidcMu.Unlock()
+	if h.oidc != nil {
+		return h.oidc, nil
+	}
+
+	cfg := config.AppConfig.OIDC
+	redirectURL := config.AppConfig.OIDCRedirectURL()
+	if redirectURL == "" {
+		return nil, errors.New("oidc: redirect_url or server.base_url must be configured")
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	provider, err := oidc.Discover(ctx, oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       cfg.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h.oidc = provider
+	return provider, nil
+}
+
+// resolveOIDCUser 按 issuer + subject 查找用户；找不到时按配置关联已验证邮箱的账号或自动创建
+func (h *AuthHandler) resolveOIDCUser(idToken *oidc.IDToken) (*model.User, error) {
+	cfg := config.AppConfig.OIDC
+	user, err := h.repo.FindByOIDCSubject(cfg.Issuer, idToken.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if cfg.LinkByEmail && idToken.Email != "" && idToken.EmailVerified {
+		users, err := h.repo.FindByEmail(idToken.Email)
+		if err != nil {
+			return nil, err
+		}
+		// 多个账号共用邮箱时无法确定关联哪一个，交给自动创建或拒绝
+		if len(users) == 1 && users[0].OIDCSubject == nil {
+			if err := h.repo.LinkOIDC(users[0].ID, cfg.Issuer, idToken.Subject); err != nil {
+				return nil, err
+			}
+			return h.repo.FindByID(users[0].ID)
+		}
+	}
+
+	if !cfg.AllowSignup {
+		return nil, errOIDCSignupDisabled
+	}
+
+	// 单点登录用户没有本地密码，写入随机哈希使密码登录始终失败，需要时可通过找回密码设置
+	random, _, err := securetoken.Generate("")
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer, subject := cfg.Issuer, idToken.Subject
+	base := oidcUsername(idToken)
+	for attempt := 0; attempt < 5; attempt++ {
+		username := base
+		if attempt > 0 {
+			username = fmt.Sprintf("%s-%04d", base, rand.Intn(10000))
+		}
+		if h.repo.ExistsByUsername(username) {
+			continue
+		}
+
+		user := &model.User{
+			Username:    username,
+			Password:    string(hashedPassword),
+			Email:       idToken.Email,
+			Role:        model.RoleUser,
+			OIDCIssuer:  &issuer,
+			OIDCSubject: &subject,
+		}
+		if err := h.repo.Create(user); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	return nil, errors.New("could not allocate a unique username")
+}
+
+// oidcUsername 依次尝试 preferred_username、邮箱前缀和 subject，清理成合法的用户名
+func oidcUsername(idToken *oidc.IDToken) string {
+	candidates := []string{idToken.PreferredUsername, strings.Split(idToken.Email, "@")[0], "sso-" + idToken.Subject}
+	for _, candidate := range candidates {
+		name := strings.Trim(usernameUnsafeChars.ReplaceAllString(candidate, ""), ".-")
+		if len(name) > 40 {
+			name = name[:40]
+		}
+		if len(name) >= 3 {
+			return name
+		}
+	}
+	return "sso-user"
+}
+
+// safeRedirectPath 只接受站内路径，防止登录后被带到外部站点
+func safeRedirectPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return "/"
+	}
+	return path
+}
+
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
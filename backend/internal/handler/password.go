@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/securetoken"
+)
+
+// 找回密码令牌
+const (
+	passwordResetPrefix = "omp_"
+	passwordResetTTL    = 30 * time.Minute
+)
+
+// ChangePassword godoc
+// @Summary Change password after verifying the old one; other sessions are signed out
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	user, err := h.repo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "原密码错误"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "密码加密失败"})
+		return
+	}
+
+	if err := h.repo.UpdatePassword(userID, string(hashedPassword)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "修改密码失败"})
+		return
+	}
+
+	if _, err := h.sessions.RevokeOthers(userID, c.GetInt64("sessionID"), model.SessionRevokedPassword, time.Now()); err != nil {
+		log.Printf("Failed to revoke sessions after password change for user %d: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password changed"})
+}
+
+// ForgotPassword godoc
+// @Summary Email a single-use password reset link
+// @Description Always succeeds so the response does not reveal whether the email is registered
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 重置链接只能指向配置的地址，根据请求的 Host 生成会把令牌发给伪造的域名
+	baseURL := publicBaseURL()
+	if baseURL == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "未配置 server.base_url，无法发送重置链接"})
+		return
+	}
+
+	now := time.Now()
+	decision, err := h.guard.Throttle(req.Email, c.ClientIP(), now)
+	if err != nil {
+		log.Printf("Failed to check password reset limiter: %v", err)
+	} else if !decision.Allowed {
+		seconds := int(math.Ceil(decision.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       fmt.Sprintf("请求过于频繁，请 %d 秒后再试", seconds),
+			"retry_after": seconds,
+		})
+		return
+	}
+
+	users, err := h.repo.FindByEmail(req.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, user := range users {
+		plain, hash, err := securetoken.Generate(passwordResetPrefix)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "生成令牌失败"})
+			return
+		}
+		if err := h.repo.CreatePasswordReset(&model.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hash,
+			ExpiresAt: now.Add(passwordResetTTL),
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		link := baseURL + "/reset-password?token=" + url.QueryEscape(plain)
+		go h.sendPasswordReset(user, link)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "如果该邮箱已注册，重置链接已发送"})
+}
+
+// ResetPassword godoc
+// @Summary Set a new password with a reset token; all sessions are signed out
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.repo.FindPasswordReset(securetoken.Hash(req.Token))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "重置链接无效或已过期"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "密码加密失败"})
+		return
+	}
+
+	reset, err := h.repo.ResetPassword(token, string(hashedPassword), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "重置密码失败"})
+		return
+	}
+	if !reset {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "重置链接无效或已过期"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password reset"})
+}
+
+// DeleteAccount godoc
+// @Summary Permanently delete the account together with all of its data
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	var req model.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	user, err := h.repo.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "密码错误"})
+		return
+	}
+
+	if err := h.repo.Delete(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "注销账号失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+func (h *AuthHandler) sendPasswordReset(user model.User, link string) {
+	body := fmt.Sprintf("你好 %s：\n\n我们收到了重置 OfferMatrix 密码的请求。请在 %d 分钟内打开以下链接设置新密码：\n\n%s\n\n如果这不是你本人的操作，请忽略这封邮件，你的密码不会改变。\n",
+		user.Username, int(passwordResetTTL.Minutes()), link)
+	if err := h.mailer.Send(user.Email, "重置你的 OfferMatrix 密码", body); err != nil {
+		log.Printf("Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+)
+
+// ListSessions godoc
+// @Summary List active login sessions of the current user
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.sessions.FindActiveByUser(c.GetInt64("userID"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := c.GetInt64("sessionID")
+	resp := make([]model.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, model.SessionResponse{
+			ID:         s.ID,
+			Device:     s.Device,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID == current,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeSession godoc
+// @Summary Sign out a single session; revoking the current one is equivalent to logout
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.sessions.RevokeForUser(c.GetInt64("userID"), id, model.SessionRevokedByUser, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "revoked"})
+}
+
+// RevokeOtherSessions godoc
+// @Summary Sign out everywhere except the current session
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	revoked, err := h.sessions.RevokeOthers(c.GetInt64("userID"), c.GetInt64("sessionID"),
+		model.SessionRevokedOthers, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// describeDevice 从 User-Agent 粗略识别浏览器和操作系统，用于会话列表展示
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "未知设备"
+	}
+
+	browser := "未知浏览器"
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "MicroMessenger"):
+		browser = "微信"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	case strings.HasPrefix(userAgent, "curl/"):
+		return "curl"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		platform = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		platform = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		platform = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"):
+		platform = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		platform = "Linux"
+	}
+
+	if platform == "" {
+		return browser
+	}
+	return browser + " / " + platform
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+type StatsHandler struct {
+	repo *repository.StatsRepository
+}
+
+func NewStatsHandler() *StatsHandler {
+	return &StatsHandler{
+		repo: repository.NewStatsRepository(),
+	}
+}
+
+func (h *StatsHandler) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/stats", h.Get)
+}
+
+// Get godoc
+// @Summary Aggregated statistics, optionally limited by ?start=&end=
+// @Description Applications are filtered by creation time, interviews by start time
+func (h *StatsHandler) Get(c *gin.Context) {
+	var rng repository.DateRange
+	if s := c.Query("start"); s != "" {
+		start, err := parseTimeBound(s, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start time format"})
+			return
+		}
+		rng.Start = &start
+	}
+	if s := c.Query("end"); s != "" {
+		end, err := parseTimeBound(s, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end time format"})
+			return
+		}
+		rng.End = &end
+	}
+	if rng.Start != nil && rng.End != nil && rng.End.Before(*rng.Start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end must not be before start"})
+		return
+	}
+
+	stats, err := h.collect(c.GetInt64("userID"), rng)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *StatsHandler) collect(userID int64, rng repository.DateRange) (*model.Stats, error) {
+	stats := &model.Stats{}
+
+	statusCounts, err := h.repo.StatusCounts(userID, rng)
+	if err != nil {
+		return nil, err
+	}
+	stats.StatusCounts = statusCounts
+	for _, sc := range statusCounts {
+		stats.TotalApplications += sc.Count
+	}
+
+	// 拿到 Offer 后拒绝或被撤回的申请当前状态已不是 OFFER，按流转记录统计
+	if stats.OfferCount, err = h.repo.OfferCount(userID, rng); err != nil {
+		return nil, err
+	}
+	if stats.TotalApplications > 0 {
+		stats.OfferRate = float64(stats.OfferCount) / float64(stats.TotalApplications)
+	}
+
+	stats.TotalInterviews, stats.FinishedInterviews, stats.ReviewedInterviews, err = h.repo.InterviewCounts(userID, rng)
+	if err != nil {
+		return nil, err
+	}
+	if stats.FinishedInterviews > 0 {
+		stats.ReviewCompletionRate = float64(stats.ReviewedInterviews) / float64(stats.FinishedInterviews)
+	}
+
+	if stats.InterviewsByWeek, err = h.repo.InterviewsByWeek(userID, rng); err != nil {
+		return nil, err
+	}
+	if stats.InterviewsByMonth, err = h.repo.InterviewsByMonth(userID, rng); err != nil {
+		return nil, err
+	}
+	if stats.RoundDistribution, err = h.repo.RoundDistribution(userID, rng); err != nil {
+		return nil, err
+	}
+	if stats.AvgDaysToOffer, err = h.repo.AvgDaysToOffer(userID, rng); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/jwt"
+	"offermatrix/pkg/securetoken"
+	"offermatrix/pkg/totp"
+)
+
+const (
+	totpIssuer        = "OfferMatrix"
+	totpSkew          = 1
+	recoveryCodeCount = 10
+)
+
+// LoginTwoFactor godoc
+// @Summary Exchange a login challenge token plus a TOTP or recovery code for real tokens
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req model.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := jwt.ParseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "验证已过期，请重新登录"})
+		return
+	}
+
+	now := time.Now()
+	ip := c.ClientIP()
+	decision, err := h.guard.Check(claims.Username, ip, now)
+	if err != nil {
+		log.Printf("Failed to check login limiter: %v", err)
+	} else if !decision.Allowed {
+		respondLoginThrottled(c, decision)
+		return
+	}
+
+	user, err := h.repo.FindByID(claims.UserID)
+	if err != nil || !user.TOTPEnabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "验证已过期，请重新登录"})
+		return
+	}
+
+	ok, err := h.verifySecondFactor(user, req.Code, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		h.recordLoginFailure(user.Username, ip, user, now)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "验证码错误"})
+		return
+	}
+
+	h.completeLogin(c, user)
+}
+
+// TwoFactorStatus godoc
+// @Summary Two-factor authentication status of the current user
+func (h *AuthHandler) TwoFactorStatus(c *gin.Context) {
+	user, err := h.repo.FindByID(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	status := model.TwoFactorStatus{Enabled: user.TOTPEnabled}
+	if user.TOTPEnabled {
+		if status.RecoveryCodesRemaining, err = h.repo.CountRecoveryCodes(user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetupTwoFactor godoc
+// @Summary Generate a new TOTP secret; it takes effect only after EnableTwoFactor confirms a code
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	user, err := h.repo.FindByID(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "两步验证已开启"})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成密钥失败"})
+		return
+	}
+	if err := h.repo.SetTOTPSecret(user.ID, secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURL: totp.URI(totpIssuer, user.Username, secret),
+	})
+}
+
+// EnableTwoFactor godoc
+// @Summary Confirm the pending TOTP secret with a code and receive recovery codes
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.repo.FindByID(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "两步验证已开启"})
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请先生成密钥"})
+		return
+	}
+
+	counter, ok := totp.Verify(user.TOTPSecret, req.Code, time.Now(), totpSkew)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "验证码错误"})
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成恢复码失败"})
+		return
+	}
+	if err := h.repo.EnableTOTP(user.ID, counter, hashes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Turn off two-factor authentication after verifying password and a code
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req model.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.repo.FindByID(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "两步验证未开启"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "密码错误"})
+		return
+	}
+
+	ok, err := h.verifySecondFactor(user, req.Code, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "验证码错误"})
+		return
+	}
+
+	if err := h.repo.DisableTOTP(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Replace all recovery codes; requires a current TOTP code
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.repo.FindByID(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "两步验证未开启"})
+		return
+	}
+
+	ok, err := h.verifyTOTP(user, req.Code, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "验证码错误"})
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成恢复码失败"})
+		return
+	}
+	if err := h.repo.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// verifySecondFactor 6 位数字按 TOTP 校验，其余按恢复码校验
+func (h *AuthHandler) verifySecondFactor(user *model.User, code string, now time.Time) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && strings.Trim(code, "0123456789") == "" {
+		return h.verifyTOTP(user, code, now)
+	}
+	return h.repo.UseRecoveryCode(user.ID, securetoken.Hash(normalizeRecoveryCode(code)), now)
+}
+
+// verifyTOTP 校验验证码并推进时间步，同一验证码只能使用一次
+func (h *AuthHandler) verifyTOTP(user *model.User, code string, now time.Time) (bool, error) {
+	counter, ok := totp.Verify(user.TOTPSecret, code, now, totpSkew)
+	if !ok {
+		return false, nil
+	}
+	return h.repo.AdvanceTOTPCounter(user.ID, counter)
+}
+
+// newRecoveryCodes 生成形如 3f9a1-c07be 的恢复码，返回明文和摘要
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(b)
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, securetoken.Hash(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格和连字符
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
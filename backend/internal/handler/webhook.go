@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+	"offermatrix/internal/webhook"
+	"offermatrix/pkg/netguard"
+)
+
+type WebhookHandler struct {
+	repo       *repository.WebhookRepository
+	dispatcher *webhook.Dispatcher
+}
+
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{
+		repo:       repository.NewWebhookRepository(),
+		dispatcher: webhook.NewDispatcher(),
+	}
+}
+
+func (h *WebhookHandler) RegisterRoutes(r *gin.RouterGroup) {
+	hooks := r.Group("/webhooks")
+	{
+		hooks.GET("", h.List)
+		hooks.GET("/events", h.Events)
+		hooks.POST("", h.Create)
+		hooks.PUT("/:id", h.Update)
+		hooks.DELETE("/:id", h.Delete)
+		hooks.GET("/:id/deliveries", h.Deliveries)
+		hooks.POST("/:id/test", h.SendTest)
+		hooks.POST("/deliveries/:deliveryId/redeliver", h.Redeliver)
+	}
+}
+
+// List godoc
+// @Summary List webhook subscriptions of the current user
+func (h *WebhookHandler) List(c *gin.Context) {
+	subs, err := h.repo.FindAll(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+// Events godoc
+// @Summary List subscribable events
+func (h *WebhookHandler) Events(c *gin.Context) {
+	c.JSON(http.StatusOK, model.WebhookEvents)
+}
+
+// Create godoc
+// @Summary Create a webhook subscription, the signing secret is only returned here
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req model.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := normalizeEvents(req.Events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateWebhookURL(c.Request.Context(), req.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := &model.WebhookSubscription{
+		UserID:      c.GetInt64("userID"),
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      events,
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := h.repo.Create(sub); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	sub.EventList = strings.Split(sub.Events, ",")
+
+	c.JSON(http.StatusCreated, model.CreateWebhookResponse{WebhookSubscription: *sub, Secret: secret})
+}
+
+// Update godoc
+// @Summary Update a webhook subscription
+func (h *WebhookHandler) Update(c *gin.Context) {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.URL != "" {
+		if err := validateWebhookURL(c.Request.Context(), req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sub.URL = req.URL
+	}
+	if len(req.Events) > 0 {
+		events, err := normalizeEvents(req.Events)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sub.Events = events
+		sub.EventList = strings.Split(events, ",")
+	}
+	if req.Description != "" {
+		sub.Description = req.Description
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	if err := h.repo.Update(sub); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// Delete godoc
+// @Summary Delete a webhook subscription and its delivery logs
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.Delete(c.GetInt64("userID"), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// Deliveries godoc
+// @Summary List recent deliveries of a webhook subscription
+// @Param limit query int false "Max records, default 50"
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	deliveries, err := h.repo.FindDeliveries(sub.UserID, sub.ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// SendTest godoc
+// @Summary Enqueue a webhook.test event for the subscription
+func (h *WebhookHandler) SendTest(c *gin.Context) {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.dispatcher.EmitTo(sub, model.EventWebhookTest, gin.H{
+		"message":         "This is a test event from OfferMatrix",
+		"subscription_id": sub.ID,
+		"sent_at":         time.Now(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// Redeliver godoc
+// @Summary Retry a delivery immediately
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	if err := h.repo.Redeliver(userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	delivery, _ := h.repo.FindDelivery(userID, id)
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func (h *WebhookHandler) findSubscription(c *gin.Context) (*model.WebhookSubscription, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+
+	sub, err := h.repo.FindByID(c.GetInt64("userID"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+		return nil, false
+	}
+	return sub, true
+}
+
+// normalizeEvents 校验事件名并拼接为逗号分隔字符串
+func normalizeEvents(events []string) (string, error) {
+	seen := make(map[string]bool)
+	var result []string
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if e == "*" {
+			return "*", nil
+		}
+		valid := false
+		for _, known := range model.WebhookEvents {
+			if e == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", errors.New("unknown event " + e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			result = append(result, e)
+		}
+	}
+	if len(result) == 0 {
+		return "", errors.New("events is required")
+	}
+	return strings.Join(result, ","), nil
+}
+
+// validateWebhookURL 要求回调地址是公网的 http(s) 地址，不允许指向本机、内网或元数据服务
+func validateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url must be an absolute http(s) url")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := netguard.CheckHost(ctx, u.Hostname()); err != nil {
+		if errors.Is(err, netguard.ErrForbiddenAddress) {
+			return errors.New("url must point to a public address")
+		}
+		return errors.New("url host cannot be resolved")
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+// WorkspaceHandler 求职者邀请教练加入自己的工作区，教练接受后按授予的权限访问求职者的数据
+type WorkspaceHandler struct {
+	repo  *repository.WorkspaceRepository
+	users *repository.UserRepository
+}
+
+func NewWorkspaceHandler() *WorkspaceHandler {
+	return &WorkspaceHandler{
+		repo:  repository.NewWorkspaceRepository(),
+		users: repository.NewUserRepository(),
+	}
+}
+
+func (h *WorkspaceHandler) RegisterRoutes(r *gin.RouterGroup) {
+	workspaces := r.Group("/workspaces")
+	{
+		// 求职者管理自己的工作区
+		workspaces.GET("/mine", h.GetMine)
+		workspaces.POST("/mine/members", h.Invite)
+		workspaces.PUT("/mine/members/:id", h.UpdateMember)
+		workspaces.DELETE("/mine/members/:id", h.RevokeMember)
+
+		// 教练处理收到的邀请和已加入的工作区
+		workspaces.GET("/joined", h.ListJoined)
+		workspaces.POST("/joined/:id/accept", h.Accept)
+		workspaces.POST("/joined/:id/decline", h.Decline)
+		workspaces.DELETE("/joined/:id", h.Leave)
+	}
+}
+
+// GetMine godoc
+// @Summary Get the current user's workspace with its members and pending invitations
+func (h *WorkspaceHandler) GetMine(c *gin.Context) {
+	ws, err := h.repo.FindByOwner(c.GetInt64("userID"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusOK, model.WorkspaceResponse{Members: []model.WorkspaceMemberResponse{}})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	members, err := h.repo.FindMembers(ws.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.WorkspaceResponse{Workspace: ws, Members: members})
+}
+
+// Invite godoc
+// @Summary Invite a coach by username with scoped permissions
+func (h *WorkspaceHandler) Invite(c *gin.Context) {
+	var req model.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt64("userID")
+	invitee, err := h.users.FindByUsername(req.Username)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+	if invitee.ID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "不能邀请自己"})
+		return
+	}
+
+	// 个人访问令牌的请求上下文里没有用户名，从数据库读取
+	owner, err := h.users.FindByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "用户不存在"})
+		return
+	}
+
+	ws, err := h.repo.EnsureForOwner(userID, owner.Username+" 的求职工作区")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	member := &model.WorkspaceMember{
+		WorkspaceID: ws.ID,
+		UserID:      invitee.ID,
+		Permissions: model.JoinPermissions(req.Permissions),
+		Status:      model.MemberPending,
+		InvitedBy:   userID,
+	}
+	invited, err := h.repo.Invite(member)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !invited {
+		c.JSON(http.StatusConflict, gin.H{"error": "该用户已是成员或邀请尚未处理"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.WorkspaceMemberResponse{
+		ID:          member.ID,
+		UserID:      invitee.ID,
+		Username:    invitee.Username,
+		Permissions: member.PermissionList(),
+		Status:      member.Status,
+		CreatedAt:   member.CreatedAt,
+	})
+}
+
+// UpdateMember godoc
+// @Summary Change the permissions granted to a member or pending invitation
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	ws, member, ok := h.findOwnMember(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member.Permissions = model.JoinPermissions(req.Permissions)
+	if err := h.repo.UpdatePermissions(ws.ID, member.ID, member.Permissions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RevokeMember godoc
+// @Summary Cancel a pending invitation or remove a member
+func (h *WorkspaceHandler) RevokeMember(c *gin.Context) {
+	ws, member, ok := h.findOwnMember(c)
+	if !ok {
+		return
+	}
+
+	err := h.repo.Transition(member.ID, ws.ID, 0,
+		[]string{model.MemberPending, model.MemberAccepted}, model.MemberRevoked, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "成员已不在工作区中"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "revoked"})
+}
+
+// ListJoined godoc
+// @Summary List invitations received and workspaces joined by the current user
+func (h *WorkspaceHandler) ListJoined(c *gin.Context) {
+	memberships, err := h.repo.FindMemberships(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, memberships)
+}
+
+// Accept godoc
+// @Summary Accept a workspace invitation
+func (h *WorkspaceHandler) Accept(c *gin.Context) {
+	h.respond(c, []string{model.MemberPending}, model.MemberAccepted)
+}
+
+// Decline godoc
+// @Summary Decline a workspace invitation
+func (h *WorkspaceHandler) Decline(c *gin.Context) {
+	h.respond(c, []string{model.MemberPending}, model.MemberDeclined)
+}
+
+// Leave godoc
+// @Summary Leave a joined workspace
+func (h *WorkspaceHandler) Leave(c *gin.Context) {
+	h.respond(c, []string{model.MemberAccepted}, model.MemberLeft)
+}
+
+// respond 教练处理自己的成员记录，只有当前状态属于 from 时才生效
+func (h *WorkspaceHandler) respond(c *gin.Context, from []string, to string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.repo.Transition(id, 0, c.GetInt64("userID"), from, to, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invitation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": to})
+}
+
+// findOwnMember 在当前用户自己的工作区中查找成员，找不到时直接写 404
+func (h *WorkspaceHandler) findOwnMember(c *gin.Context) (*model.Workspace, *model.WorkspaceMember, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, nil, false
+	}
+
+	ws, err := h.repo.FindByOwner(c.GetInt64("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return nil, nil, false
+	}
+	member, err := h.repo.FindMember(ws.ID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return nil, nil, false
+	}
+	return ws, member, true
+}
+
+// workspaceAccess 描述本次请求访问的是谁的数据、拥有哪些权限
+type workspaceAccess struct {
+	OwnerID int64
+	member  *model.WorkspaceMember
+}
+
+// IsOwner 是否在访问自己的数据
+func (a workspaceAccess) IsOwner() bool {
+	return a.member == nil
+}
+
+func (a workspaceAccess) Can(permission string) bool {
+	return a.member == nil || a.member.Can(permission)
+}
+
+// redactReview 没有查看复盘权限时清空面试复盘正文
+func (a workspaceAccess) redactReview(interview *model.Interview) {
+	if !a.Can(model.PermViewReviews) {
+		interview.ReviewContent = ""
+	}
+}
+
+func (a workspaceAccess) redactReviews(interviews []model.Interview) {
+	for i := range interviews {
+		a.redactReview(&interviews[i])
+	}
+}
+
+// resolveAccess 根据 workspace_id 查询参数确定访问对象：不带参数时访问自己的数据；
+// 带参数时要求当前用户是该工作区已接受邀请的成员且拥有 permission，否则写入错误响应并返回 false
+func resolveAccess(c *gin.Context, workspaces *repository.WorkspaceRepository, permission string) (workspaceAccess, bool) {
+	userID := c.GetInt64("userID")
+	param := c.Query("workspace_id")
+	if param == "" {
+		return workspaceAccess{OwnerID: userID}, true
+	}
+
+	workspaceID, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace_id"})
+		return workspaceAccess{}, false
+	}
+
+	ws, member, err := workspaces.FindAccess(workspaceID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "workspace not found"})
+			return workspaceAccess{}, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return workspaceAccess{}, false
+	}
+	if member != nil && !member.Can(permission) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "没有该工作区的相应权限"})
+		return workspaceAccess{}, false
+	}
+
+	return workspaceAccess{OwnerID: ws.OwnerID, member: member}, true
+}
@@ -0,0 +1,69 @@
+package loginlimit
+
+import (
+	"time"
+
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+// DBLimiter 把计数保存在 login_attempts 表，多个实例共享同一份状态
+type DBLimiter struct {
+	policy   Policy
+	attempts *repository.LoginAttemptRepository
+}
+
+func NewDBLimiter(policy Policy) *DBLimiter {
+	return &DBLimiter{
+		policy:   policy,
+		attempts: repository.NewLoginAttemptRepository(),
+	}
+}
+
+func (l *DBLimiter) Status(key string, now time.Time) (Status, error) {
+	a, err := l.attempts.Find(key)
+	if err != nil {
+		return Status{}, err
+	}
+	if a.LastFailureAt == nil {
+		return Status{}, nil
+	}
+	status := toStatus(a)
+	if l.policy.expired(*a.LastFailureAt, now) && !status.Blocked(now) {
+		return Status{}, nil
+	}
+	return status, nil
+}
+
+func (l *DBLimiter) RecordFailure(key string, now time.Time) (Status, error) {
+	a, err := l.attempts.Update(key, func(a *model.LoginAttempt) {
+		if a.LastFailureAt == nil || l.policy.expired(*a.LastFailureAt, now) {
+			a.Failures = 0
+		}
+		a.Failures++
+		a.LastFailureAt = &now
+
+		retryAt, locked := l.policy.next(a.Failures, now)
+		a.RetryAt = nil
+		if !retryAt.IsZero() {
+			a.RetryAt = &retryAt
+		}
+		a.Locked = locked
+	})
+	if err != nil {
+		return Status{}, err
+	}
+	return toStatus(a), nil
+}
+
+func (l *DBLimiter) Reset(key string) error {
+	return l.attempts.Delete(key)
+}
+
+func toStatus(a *model.LoginAttempt) Status {
+	status := Status{Failures: a.Failures, Locked: a.Locked}
+	if a.RetryAt != nil {
+		status.RetryAt = *a.RetryAt
+	}
+	return status
+}
@@ -0,0 +1,134 @@
+package loginlimit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"offermatrix/internal/config"
+)
+
+// Decision 是登录前检查的结果；Locked 表示用户名被锁定，否则是退避或来源 IP 被限流
+type Decision struct {
+	Allowed    bool
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+// Guard 同时按用户名和来源 IP 限制登录尝试：
+// 前者防止针对单个账号猜密码，后者防止同一来源轮换用户名撞库
+type Guard struct {
+	users Limiter
+	ips   Limiter
+}
+
+func NewGuard(users, ips Limiter) *Guard {
+	return &Guard{users: users, ips: ips}
+}
+
+// New 根据配置创建 Guard，limiter 为 memory（默认）或 database
+func New(cfg config.LoginConfig) (*Guard, error) {
+	lockoutAfter := cfg.LockoutThreshold
+	if lockoutAfter <= 0 {
+		lockoutAfter = 10
+	}
+	lockout := time.Duration(cfg.LockoutMinutes) * time.Minute
+	if lockout <= 0 {
+		lockout = 15 * time.Minute
+	}
+
+	userPolicy := Policy{
+		BackoffAfter:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutAfter:    lockoutAfter,
+		LockoutDuration: lockout,
+		Window:          lockout,
+	}
+	ipPolicy := Policy{
+		BackoffAfter:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutAfter:    100,
+		LockoutDuration: lockout,
+		Window:          lockout,
+	}
+
+	switch cfg.Limiter {
+	case "", "memory":
+		return NewGuard(NewMemoryLimiter(userPolicy), NewMemoryLimiter(ipPolicy)), nil
+	case "database":
+		return NewGuard(NewDBLimiter(userPolicy), NewDBLimiter(ipPolicy)), nil
+	default:
+		return nil, fmt.Errorf("unknown login limiter %q", cfg.Limiter)
+	}
+}
+
+// Check 在校验密码之前调用，被拒绝时不应再比对密码
+func (g *Guard) Check(username, ip string, now time.Time) (Decision, error) {
+	return g.check(userKey(username), ip, now)
+}
+
+// Throttle 用于找回密码等会发送邮件的接口：每次请求都计入次数，超出频率后拒绝，
+// 防止借此轰炸他人收件箱。计数与登录失败分开，不会因此锁定账号登录
+func (g *Guard) Throttle(email, ip string, now time.Time) (Decision, error) {
+	key := resetKey(email)
+	decision, err := g.check(key, ip, now)
+	if err != nil || !decision.Allowed {
+		return decision, err
+	}
+	if _, err := g.users.RecordFailure(key, now); err != nil {
+		return Decision{}, err
+	}
+	if _, err := g.ips.RecordFailure(ipKey(ip), now); err != nil {
+		return Decision{}, err
+	}
+	return decision, nil
+}
+
+func (g *Guard) check(key, ip string, now time.Time) (Decision, error) {
+	ipStatus, err := g.ips.Status(ipKey(ip), now)
+	if err != nil {
+		return Decision{}, err
+	}
+	if ipStatus.Blocked(now) {
+		return Decision{RetryAfter: ipStatus.RetryAfter(now)}, nil
+	}
+
+	status, err := g.users.Status(key, now)
+	if err != nil {
+		return Decision{}, err
+	}
+	if status.Blocked(now) {
+		return Decision{Locked: status.Locked, RetryAfter: status.RetryAfter(now)}, nil
+	}
+
+	return Decision{Allowed: true}, nil
+}
+
+// Fail 记录一次失败，返回用户名和来源 IP 的最新状态
+func (g *Guard) Fail(username, ip string, now time.Time) (user Status, source Status, err error) {
+	if user, err = g.users.RecordFailure(userKey(username), now); err != nil {
+		return
+	}
+	source, err = g.ips.RecordFailure(ipKey(ip), now)
+	return
+}
+
+// Succeed 登录成功后清除用户名的失败计数；来源 IP 的计数保留到窗口结束
+func (g *Guard) Succeed(username string) error {
+	return g.users.Reset(userKey(username))
+}
+
+// userKey 用户名比较不区分大小写，与数据库默认排序规则一致
+func userKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+func resetKey(email string) string {
+	return "reset:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,99 @@
+package loginlimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestGuard() *Guard {
+	ipPolicy := testPolicy
+	ipPolicy.BackoffAfter = 5
+	ipPolicy.LockoutAfter = 20
+	return NewGuard(NewMemoryLimiter(testPolicy), NewMemoryLimiter(ipPolicy))
+}
+
+func TestGuardUsernameIsCaseInsensitive(t *testing.T) {
+	g := newTestGuard()
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < testPolicy.BackoffAfter; i++ {
+		g.Fail("Alice", "10.0.0.1", now)
+	}
+	if d, _ := g.Check(" alice ", "10.0.0.2", now); d.Allowed {
+		t.Fatal("backoff did not apply to the same username in a different case")
+	}
+	if d, _ := g.Check("bob", "10.0.0.2", now); !d.Allowed {
+		t.Fatal("another username was blocked")
+	}
+}
+
+func TestGuardBlocksSourceIPAcrossUsernames(t *testing.T) {
+	g := newTestGuard()
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		g.Fail("user"+string(rune('a'+i)), "10.0.0.1", now)
+	}
+	if d, _ := g.Check("someone-else", "10.0.0.1", now); d.Allowed || d.Locked {
+		t.Fatalf("Check from throttled IP = %+v, want rejected without account lock", d)
+	}
+	if d, _ := g.Check("someone-else", "10.0.0.2", now); !d.Allowed {
+		t.Fatal("another IP was blocked")
+	}
+}
+
+func TestGuardLockout(t *testing.T) {
+	g := newTestGuard()
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	var user Status
+	for i := 0; i < testPolicy.LockoutAfter; i++ {
+		// 换 IP 猜密码同样会锁定账号
+		user, _, _ = g.Fail("alice", "10.0.0."+string(rune('1'+i)), now)
+	}
+	if !user.Locked {
+		t.Fatalf("user status = %+v, want locked", user)
+	}
+	d, _ := g.Check("alice", "10.0.1.1", now)
+	if d.Allowed || !d.Locked || d.RetryAfter != testPolicy.LockoutDuration {
+		t.Fatalf("Check = %+v, want locked for %v", d, testPolicy.LockoutDuration)
+	}
+}
+
+func TestGuardSucceedKeepsIPCount(t *testing.T) {
+	g := newTestGuard()
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		g.Fail("alice", "10.0.0.1", now)
+	}
+	if err := g.Succeed("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := g.Check("alice", "10.0.0.2", now); !d.Allowed {
+		t.Error("username still blocked after Succeed")
+	}
+	if d, _ := g.Check("alice", "10.0.0.1", now); d.Allowed {
+		t.Error("Succeed cleared the source IP count")
+	}
+}
+
+func TestGuardThrottle(t *testing.T) {
+	g := newTestGuard()
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	// 每次请求都计数，达到退避次数后拒绝
+	for i := 0; i < testPolicy.BackoffAfter; i++ {
+		if d, err := g.Throttle("a@example.com", "10.0.0.1", now); err != nil || !d.Allowed {
+			t.Fatalf("request %d = %+v, %v, want allowed", i+1, d, err)
+		}
+	}
+	if d, _ := g.Throttle("A@example.com", "10.0.0.2", now); d.Allowed {
+		t.Fatal("throttle did not apply to the same email")
+	}
+
+	// 与登录计数分开，不影响同名账号登录
+	if d, _ := g.Check("a@example.com", "10.0.0.2", now); !d.Allowed {
+		t.Fatal("password reset requests blocked login")
+	}
+}
@@ -0,0 +1,68 @@
+// Package loginlimit 记录登录失败次数，实现渐进退避和临时锁定，防止暴力破解密码
+package loginlimit
+
+import (
+	"time"
+)
+
+// Policy 描述失败多少次后开始退避、多少次后锁定。
+// Window 内没有新的失败时计数清零，应不短于 LockoutDuration，锁定结束后重新计数
+type Policy struct {
+	BackoffAfter    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// Status 是某个 key 的当前状态；RetryAt 之前的登录请求应直接拒绝
+type Status struct {
+	Failures int
+	RetryAt  time.Time
+	Locked   bool
+}
+
+// Blocked 判断 now 时刻是否仍需拒绝
+func (s Status) Blocked(now time.Time) bool {
+	return now.Before(s.RetryAt)
+}
+
+// RetryAfter 返回距离可以重试的剩余时间
+func (s Status) RetryAfter(now time.Time) time.Duration {
+	if !s.Blocked(now) {
+		return 0
+	}
+	return s.RetryAt.Sub(now)
+}
+
+// Limiter 是失败计数的存储后端。单实例部署用 MemoryLimiter，多实例共享状态用 DBLimiter
+type Limiter interface {
+	Status(key string, now time.Time) (Status, error)
+	RecordFailure(key string, now time.Time) (Status, error)
+	Reset(key string) error
+}
+
+// expired 判断上一次失败是否已超出计数窗口
+func (p Policy) expired(lastFailure, now time.Time) bool {
+	return lastFailure.IsZero() || now.Sub(lastFailure) > p.Window
+}
+
+// next 根据累计失败次数计算下一次允许尝试的时间
+func (p Policy) next(failures int, now time.Time) (time.Time, bool) {
+	if p.LockoutAfter > 0 && failures >= p.LockoutAfter {
+		return now.Add(p.LockoutDuration), true
+	}
+	if p.BackoffAfter <= 0 || failures < p.BackoffAfter {
+		return time.Time{}, false
+	}
+
+	delay := p.BaseDelay
+	for i := p.BackoffAfter; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return now.Add(delay), false
+}
@@ -0,0 +1,119 @@
+package loginlimit
+
+import (
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	BackoffAfter:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        10 * time.Second,
+	LockoutAfter:    8,
+	LockoutDuration: 15 * time.Minute,
+	Window:          15 * time.Minute,
+}
+
+func TestPolicyNext(t *testing.T) {
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		failures int
+		delay    time.Duration
+		locked   bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, time.Second, false},
+		{4, 2 * time.Second, false},
+		{5, 4 * time.Second, false},
+		{6, 8 * time.Second, false},
+		{7, 10 * time.Second, false}, // 不超过 MaxDelay
+		{8, 15 * time.Minute, true},
+		{20, 15 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		retryAt, locked := testPolicy.next(tt.failures, now)
+		var delay time.Duration
+		if !retryAt.IsZero() {
+			delay = retryAt.Sub(now)
+		}
+		if delay != tt.delay || locked != tt.locked {
+			t.Errorf("next(%d) = %v, %v, want %v, %v", tt.failures, delay, locked, tt.delay, tt.locked)
+		}
+	}
+}
+
+func TestMemoryLimiterBackoffAndLockout(t *testing.T) {
+	l := NewMemoryLimiter(testPolicy)
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	for i := 1; i < testPolicy.BackoffAfter; i++ {
+		status, _ := l.RecordFailure("k", now)
+		if status.Blocked(now) {
+			t.Fatalf("blocked after %d failures", i)
+		}
+	}
+	status, _ := l.RecordFailure("k", now)
+	if !status.Blocked(now) || status.RetryAfter(now) != time.Second {
+		t.Fatalf("after %d failures: %+v, want 1s backoff", testPolicy.BackoffAfter, status)
+	}
+	if got, _ := l.Status("k", now.Add(500*time.Millisecond)); !got.Blocked(now.Add(500 * time.Millisecond)) {
+		t.Error("Status not blocked during backoff")
+	}
+	if got, _ := l.Status("k", now.Add(time.Second)); got.Blocked(now.Add(time.Second)) {
+		t.Error("Status still blocked once the backoff elapsed")
+	}
+
+	for i := testPolicy.BackoffAfter; i < testPolicy.LockoutAfter; i++ {
+		status, _ = l.RecordFailure("k", now)
+	}
+	if !status.Locked || status.RetryAfter(now) != testPolicy.LockoutDuration {
+		t.Fatalf("after %d failures: %+v, want lockout", testPolicy.LockoutAfter, status)
+	}
+
+	// 锁定期间即使超出计数窗口也保持锁定，结束后计数清零
+	later := now.Add(testPolicy.LockoutDuration - time.Second)
+	if got, _ := l.Status("k", later); !got.Locked || !got.Blocked(later) {
+		t.Errorf("Status during lockout = %+v", got)
+	}
+	after := now.Add(testPolicy.LockoutDuration + time.Second)
+	if got, _ := l.Status("k", after.Add(testPolicy.Window)); got != (Status{}) {
+		t.Errorf("Status after lockout and window = %+v, want zero", got)
+	}
+}
+
+func TestMemoryLimiterWindow(t *testing.T) {
+	l := NewMemoryLimiter(testPolicy)
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	l.RecordFailure("k", now)
+	l.RecordFailure("k", now)
+	if got, _ := l.Status("k", now.Add(testPolicy.Window)); got.Failures != 2 {
+		t.Errorf("Failures at window edge = %d, want 2", got.Failures)
+	}
+
+	// 超出窗口后的失败重新计数
+	status, _ := l.RecordFailure("k", now.Add(testPolicy.Window+time.Second))
+	if status.Failures != 1 || status.Blocked(now.Add(testPolicy.Window+time.Second)) {
+		t.Errorf("failure after window = %+v, want a fresh count", status)
+	}
+}
+
+func TestMemoryLimiterReset(t *testing.T) {
+	l := NewMemoryLimiter(testPolicy)
+	now := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < testPolicy.LockoutAfter; i++ {
+		l.RecordFailure("a", now)
+	}
+	l.RecordFailure("b", now)
+	if err := l.Reset("a"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := l.Status("a", now); got != (Status{}) {
+		t.Errorf("Status after Reset = %+v, want zero", got)
+	}
+	if got, _ := l.Status("b", now); got.Failures != 1 {
+		t.Errorf("Reset affected another key: %+v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package loginlimit
+
+import (
+	"sync"
+	"time"
+)
+
+// 内存计数器每记录这么多次失败顺带清理一次过期条目
+const sweepEvery = 1000
+
+type entry struct {
+	failures    int
+	lastFailure time.Time
+	retryAt     time.Time
+	locked      bool
+}
+
+// MemoryLimiter 把计数保存在进程内，重启后清零，只适用于单实例部署
+type MemoryLimiter struct {
+	policy  Policy
+	mu      sync.Mutex
+	entries map[string]*entry
+	writes  int
+}
+
+func NewMemoryLimiter(policy Policy) *MemoryLimiter {
+	return &MemoryLimiter{
+		policy:  policy,
+		entries: make(map[string]*entry),
+	}
+}
+
+func (l *MemoryLimiter) Status(key string, now time.Time) (Status, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok || (l.policy.expired(e.lastFailure, now) && !now.Before(e.retryAt)) {
+		return Status{}, nil
+	}
+	return Status{Failures: e.failures, RetryAt: e.retryAt, Locked: e.locked}, nil
+}
+
+func (l *MemoryLimiter) RecordFailure(key string, now time.Time) (Status, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.writes++
+	if l.writes%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	e, ok := l.entries[key]
+	if !ok {
+		e = &entry{}
+		l.entries[key] = e
+	}
+	if l.policy.expired(e.lastFailure, now) {
+		*e = entry{}
+	}
+
+	e.failures++
+	e.lastFailure = now
+	e.retryAt, e.locked = l.policy.next(e.failures, now)
+	return Status{Failures: e.failures, RetryAt: e.retryAt, Locked: e.locked}, nil
+}
+
+func (l *MemoryLimiter) Reset(key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+	return nil
+}
+
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, e := range l.entries {
+		if l.policy.expired(e.lastFailure, now) && !now.Before(e.retryAt) {
+			delete(l.entries, key)
+		}
+	}
+}
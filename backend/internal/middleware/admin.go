@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"offermatrix/internal/repository"
+)
+
+// AdminOnly 要求当前用户是管理员，需放在 AuthMiddleware 和 SessionOnly 之后。
+// 角色每次从数据库读取，撤销管理员或停用账号立即生效
+func AdminOnly() gin.HandlerFunc {
+	users := repository.NewUserRepository()
+
+	return func(c *gin.Context) {
+		user, err := users.FindByID(c.GetInt64("userID"))
+		if err != nil || !user.IsAdmin() || user.DisabledAt != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "需要管理员权限"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,14 +1,33 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
 	"offermatrix/pkg/jwt"
+	"offermatrix/pkg/securetoken"
 )
 
+// 会话、个人访问令牌活跃时间的记录粒度
+const touchInterval = time.Minute
+
+// 上下文中的认证方式
+const (
+	AuthMethodSession  = "session"
+	AuthMethodAPIToken = "api_token"
+)
+
+// AuthMiddleware 接受登录签发的 JWT 和个人访问令牌（omt_ 前缀）。
+// 只读令牌和管理员代登录的 token 只能调用 GET / HEAD 请求
 func AuthMiddleware() gin.HandlerFunc {
+	sessions := repository.NewSessionRepository()
+	apiTokens := repository.NewAPITokenRepository()
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -25,6 +44,11 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if strings.HasPrefix(parts[1], model.APITokenPrefix) {
+			authenticateAPIToken(c, apiTokens, parts[1])
+			return
+		}
+
 		claims, err := jwt.ParseToken(parts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的 token"})
@@ -32,9 +56,85 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 会话被吊销（退出登录、在其他设备上被移除、refresh token 重放）后，未过期的 access token 也一并失效
+		now := time.Now()
+		active, err := sessions.IsActive(claims.SessionID, claims.UserID, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "校验登录状态失败"})
+			c.Abort()
+			return
+		}
+		if !active {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "登录已失效，请重新登录"})
+			c.Abort()
+			return
+		}
+
+		if claims.ImpersonatorID != 0 && !isReadOnlyMethod(c.Request.Method) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "代登录为只读模式，不能修改数据"})
+			c.Abort()
+			return
+		}
+
+		if err := sessions.Touch(claims.SessionID, c.ClientIP(), now, touchInterval); err != nil {
+			log.Printf("Failed to update session %d last seen: %v", claims.SessionID, err)
+		}
+
 		// 将用户信息存入上下文
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("sessionID", claims.SessionID)
+		c.Set("authMethod", AuthMethodSession)
+		if claims.ImpersonatorID != 0 {
+			c.Set("impersonatorID", claims.ImpersonatorID)
+		}
 		c.Next()
 	}
 }
+
+func authenticateAPIToken(c *gin.Context, apiTokens *repository.APITokenRepository, raw string) {
+	now := time.Now()
+	token, err := apiTokens.FindActiveByHash(securetoken.Hash(raw), now)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "无效或已过期的访问令牌"})
+		c.Abort()
+		return
+	}
+
+	if token.Scope != model.APITokenScopeReadWrite && !isReadOnlyMethod(c.Request.Method) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只读令牌不能修改数据"})
+		c.Abort()
+		return
+	}
+
+	if err := apiTokens.Touch(token.ID, c.ClientIP(), now, touchInterval); err != nil {
+		log.Printf("Failed to update api token %d last used: %v", token.ID, err)
+	}
+
+	c.Set("userID", token.UserID)
+	c.Set("authMethod", AuthMethodAPIToken)
+	c.Set("apiTokenID", token.ID)
+	c.Next()
+}
+
+// SessionOnly 要求请求来自用户本人的交互式登录，用于令牌管理、改密码等账号安全接口，
+// 防止泄露的个人访问令牌被用来扩大权限或接管账号；管理员代登录同样不能查看这些信息
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("authMethod") != AuthMethodSession {
+			c.JSON(http.StatusForbidden, gin.H{"error": "该操作需要登录后进行，不支持访问令牌"})
+			c.Abort()
+			return
+		}
+		if c.GetInt64("impersonatorID") != 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "代登录不能访问账号安全设置"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
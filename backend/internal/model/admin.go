@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// AdminUserResponse 管理后台的用户列表项
+type AdminUserResponse struct {
+	ID               int64      `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	SSO              bool       `json:"sso"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// UserUsage 用户的数据量和活跃情况，用于排查问题和容量评估
+type UserUsage struct {
+	Applications   int64      `json:"applications"`
+	Interviews     int64      `json:"interviews"`
+	Webhooks       int64      `json:"webhooks"`
+	APITokens      int64      `json:"api_tokens"`
+	ActiveSessions int64      `json:"active_sessions"`
+	LastSeenAt     *time.Time `json:"last_seen_at"`
+}
+
+type AdminUserDetailResponse struct {
+	User  AdminUserResponse `json:"user"`
+	Usage UserUsage         `json:"usage"`
+}
+
+type UserListResponse struct {
+	Items []AdminUserResponse `json:"items"`
+	Total int64               `json:"total"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// AdminResetPasswordRequest NewPassword 为空时生成临时密码并在响应中返回一次
+type AdminResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"omitempty,min=6"`
+}
+
+// ImpersonateResponse 只读代登录的 access token，不配发 refresh token
+type ImpersonateResponse struct {
+	Token     string       `json:"token"`
+	ExpiresIn int64        `json:"expires_in"`
+	User      UserResponse `json:"user"`
+}
+
+type AuditLogListResponse struct {
+	Items []AuditLog `json:"items"`
+	Total int64      `json:"total"`
+}
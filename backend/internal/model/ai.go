@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type ParseInvitationRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// ParsedInvitation 是从面试通知中提取的结构化信息，无法识别的字段为空
+type ParsedInvitation struct {
+	CompanyName          string     `json:"company_name"`
+	RoundName            string     `json:"round_name"`
+	StartTime            *time.Time `json:"start_time"`
+	EndTime              *time.Time `json:"end_time"`
+	MeetingLink          string     `json:"meeting_link"`
+	Confidence           float64    `json:"confidence"`
+	MatchedApplicationID int64      `json:"matched_application_id"`
+}
+
+type AnalyzeJDRequest struct {
+	JobDescription string `json:"job_description"`
+}
+
+// JDAnalysisResult 是 JD 分析的结构化结果，Markdown 为渲染后写入 Application.JDAnalysis 的文本
+type JDAnalysisResult struct {
+	Responsibilities []string `json:"responsibilities"`
+	Skills           []string `json:"skills"`
+	Advantages       []string `json:"advantages"`
+	Concerns         []string `json:"concerns"`
+	SalaryAnalysis   string   `json:"salary_analysis"`
+	Markdown         string   `json:"markdown"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// APITokenPrefix 个人访问令牌的明文前缀，AuthMiddleware 据此区分令牌和 JWT
+const APITokenPrefix = "omt_"
+
+// 个人访问令牌的权限范围
+const (
+	APITokenScopeReadOnly  = "read-only"
+	APITokenScopeReadWrite = "read-write"
+)
+
+// APIToken 个人访问令牌，供脚本和第三方集成调用 API，只保存摘要。
+// Prefix 是明文的前几位，方便用户在列表中辨认
+type APIToken struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index:idx_api_token_user_id"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Scope      string     `json:"scope" gorm:"type:varchar(20);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(20)"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:uniq_api_token_hash"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip" gorm:"type:varchar(64)"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// CreateAPITokenRequest 的 ExpiresInDays 为空时默认 90 天
+type CreateAPITokenRequest struct {
+	Name          string `json:"name" binding:"required,max=100"`
+	Scope         string `json:"scope" binding:"required,oneof=read-only read-write"`
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// CreateAPITokenResponse 令牌明文只在创建时返回一次
+type CreateAPITokenResponse struct {
+	APIToken
+	Token string `json:"token"`
+}
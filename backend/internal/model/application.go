@@ -3,16 +3,20 @@ package model
 import "time"
 
 type Application struct {
-	ID             int64       `json:"id" gorm:"primaryKey;autoIncrement"`
-	CompanyName    string      `json:"company_name" gorm:"type:varchar(100);not null"`
-	JobTitle       string      `json:"job_title" gorm:"type:varchar(100)"`
-	CurrentStatus  string      `json:"current_status" gorm:"type:varchar(20);default:IN_PROCESS"`
-	Salary         string      `json:"salary" gorm:"type:varchar(100)"`
-	JobDescription string      `json:"job_description" gorm:"type:text"`
-	JDAnalysis     string      `json:"jd_analysis" gorm:"type:text"`
-	CreatedAt      time.Time   `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
-	Interviews     []Interview `json:"interviews,omitempty" gorm:"foreignKey:ApplicationID"`
+	ID             int64         `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         int64         `json:"user_id" gorm:"not null;index:idx_app_user_id"`
+	CompanyName    string        `json:"company_name" gorm:"type:varchar(100);not null"`
+	JobTitle       string        `json:"job_title" gorm:"type:varchar(100)"`
+	CurrentStatus  string        `json:"current_status" gorm:"type:varchar(20);default:IN_PROCESS"`
+	Salary         string        `json:"salary" gorm:"type:varchar(100)"`
+	City           string        `json:"city" gorm:"type:varchar(50)"`
+	JobDescription string        `json:"job_description" gorm:"type:text"`
+	JDAnalysis     string        `json:"jd_analysis" gorm:"type:text"`
+	CreatedAt      time.Time     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
+	Interviews     []Interview   `json:"interviews,omitempty" gorm:"foreignKey:ApplicationID"`
+	Compensation   *Compensation `json:"compensation,omitempty" gorm:"foreignKey:ApplicationID"`
+	Offer          *Offer        `json:"offer,omitempty" gorm:"foreignKey:ApplicationID"`
 }
 
 func (Application) TableName() string {
@@ -23,6 +27,7 @@ type CreateApplicationRequest struct {
 	CompanyName   string `json:"company_name" binding:"required"`
 	JobTitle      string `json:"job_title"`
 	CurrentStatus string `json:"current_status"`
+	StatusReason  string `json:"status_reason"`
 }
 
 type UpdateApplicationRequest struct {
@@ -30,6 +35,10 @@ type UpdateApplicationRequest struct {
 	JobTitle       string `json:"job_title"`
 	CurrentStatus  string `json:"current_status"`
 	Salary         string `json:"salary"`
+	City           string `json:"city"`
 	JobDescription string `json:"job_description"`
 	JDAnalysis     string `json:"jd_analysis"`
+	StatusReason   string `json:"status_reason"`
+	// Compensation 不为空时按结构化薪资保存；否则修改 Salary 时会尝试解析出结构化薪资
+	Compensation *CompensationRequest `json:"compensation"`
 }
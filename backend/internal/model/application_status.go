@@ -0,0 +1,81 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// 申请状态
+const (
+	StatusWishlist  = "WISHLIST"
+	StatusApplied   = "APPLIED"
+	StatusInProcess = "IN_PROCESS"
+	StatusOffer     = "OFFER"
+	StatusRejected  = "REJECTED"
+	StatusWithdrawn = "WITHDRAWN"
+	StatusGhosted   = "GHOSTED"
+)
+
+// statusTransitions 定义每个状态允许流转到的下一状态，REJECTED / WITHDRAWN 为终态
+var statusTransitions = map[string][]string{
+	StatusWishlist:  {StatusApplied, StatusWithdrawn},
+	StatusApplied:   {StatusInProcess, StatusRejected, StatusWithdrawn, StatusGhosted},
+	StatusInProcess: {StatusOffer, StatusRejected, StatusWithdrawn, StatusGhosted},
+	StatusOffer:     {StatusRejected, StatusWithdrawn},
+	StatusGhosted:   {StatusInProcess, StatusRejected, StatusWithdrawn},
+	StatusRejected:  {},
+	StatusWithdrawn: {},
+}
+
+// IsValidStatus 判断是否为已定义的申请状态
+func IsValidStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+// AllowedTransitions 返回从 from 出发允许流转到的状态
+func AllowedTransitions(from string) []string {
+	return statusTransitions[from]
+}
+
+// StatusTransitionError 表示一次不合法的状态流转
+type StatusTransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *StatusTransitionError) Error() string {
+	if !IsValidStatus(e.To) {
+		return fmt.Sprintf("unknown status %q", e.To)
+	}
+	return fmt.Sprintf("illegal status transition from %s to %s", e.From, e.To)
+}
+
+// ValidateTransition 校验 from -> to 是否合法
+func ValidateTransition(from, to string) error {
+	if !IsValidStatus(to) {
+		return &StatusTransitionError{From: from, To: to, Allowed: AllowedTransitions(from)}
+	}
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return &StatusTransitionError{From: from, To: to, Allowed: AllowedTransitions(from)}
+}
+
+// ApplicationStatusEvent 记录申请的每一次状态流转
+type ApplicationStatusEvent struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ApplicationID int64     `json:"application_id" gorm:"not null;index:idx_status_event_app_id"`
+	UserID        int64     `json:"user_id" gorm:"not null"`
+	FromStatus    string    `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus      string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	Reason        string    `json:"reason" gorm:"type:varchar(500)"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ApplicationStatusEvent) TableName() string {
+	return "application_status_events"
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		ok       bool
+	}{
+		{StatusWishlist, StatusApplied, true},
+		{StatusWishlist, StatusWithdrawn, true},
+		{StatusWishlist, StatusInProcess, false},
+		{StatusWishlist, StatusOffer, false},
+
+		{StatusApplied, StatusInProcess, true},
+		{StatusApplied, StatusRejected, true},
+		{StatusApplied, StatusGhosted, true},
+		{StatusApplied, StatusOffer, false},
+		{StatusApplied, StatusWishlist, false},
+
+		{StatusInProcess, StatusOffer, true},
+		{StatusInProcess, StatusGhosted, true},
+		{StatusInProcess, StatusApplied, false},
+
+		// 拿到 Offer 后只能拒绝或放弃
+		{StatusOffer, StatusRejected, true},
+		{StatusOffer, StatusWithdrawn, true},
+		{StatusOffer, StatusInProcess, false},
+		{StatusOffer, StatusGhosted, false},
+
+		// 沉默的公司重新联系可以回到流程中
+		{StatusGhosted, StatusInProcess, true},
+		{StatusGhosted, StatusRejected, true},
+		{StatusGhosted, StatusApplied, false},
+		{StatusGhosted, StatusOffer, false},
+
+		// 终态不能再流转
+		{StatusRejected, StatusInProcess, false},
+		{StatusRejected, StatusApplied, false},
+		{StatusWithdrawn, StatusApplied, false},
+		{StatusWithdrawn, StatusOffer, false},
+
+		// 保持原状态也不算合法流转
+		{StatusApplied, StatusApplied, false},
+	}
+	for _, tt := range tests {
+		err := ValidateTransition(tt.from, tt.to)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateTransition(%s, %s) = %v, want ok=%v", tt.from, tt.to, err, tt.ok)
+		}
+	}
+}
+
+func TestTerminalStatuses(t *testing.T) {
+	for _, status := range []string{StatusRejected, StatusWithdrawn} {
+		if next := AllowedTransitions(status); len(next) != 0 {
+			t.Errorf("AllowedTransitions(%s) = %v, want none", status, next)
+		}
+	}
+}
+
+func TestValidateTransitionError(t *testing.T) {
+	err := ValidateTransition(StatusOffer, StatusApplied)
+	var te *StatusTransitionError
+	if !errors.As(err, &te) {
+		t.Fatalf("error = %v, want *StatusTransitionError", err)
+	}
+	if len(te.Allowed) != 2 || te.Allowed[0] != StatusRejected || te.Allowed[1] != StatusWithdrawn {
+		t.Errorf("Allowed = %v", te.Allowed)
+	}
+	if got := err.Error(); got != "illegal status transition from OFFER to APPLIED" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	if err := ValidateTransition(StatusApplied, "HIRED"); err == nil || err.Error() != `unknown status "HIRED"` {
+		t.Errorf("unknown status error = %v", err)
+	}
+	if IsValidStatus("HIRED") || !IsValidStatus(StatusGhosted) {
+		t.Error("IsValidStatus mismatch")
+	}
+}
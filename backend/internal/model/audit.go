@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// 审计事件
+const (
+	AuditLoginLocked    = "login.locked"
+	AuditLoginIPBlocked = "login.ip_blocked"
+
+	AuditAdminListUsers     = "admin.list_users"
+	AuditAdminViewUser      = "admin.view_user"
+	AuditAdminUpdateRole    = "admin.update_role"
+	AuditAdminDisableUser   = "admin.disable_user"
+	AuditAdminEnableUser    = "admin.enable_user"
+	AuditAdminResetPassword = "admin.reset_password"
+	AuditAdminImpersonate   = "admin.impersonate"
+	AuditAdminViewAudit     = "admin.view_audit_logs"
+)
+
+// AuditLog 记录安全相关事件。UserID 为 0 表示事件无法归属到具体用户；
+// 管理员操作的 UserID 为执行操作的管理员，Subject 为被操作的对象
+type AuditLog struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `json:"user_id" gorm:"index:idx_audit_user_id"`
+	Action    string    `json:"action" gorm:"type:varchar(50);not null;index:idx_audit_action"`
+	Subject   string    `json:"subject" gorm:"type:varchar(255)"`
+	IP        string    `json:"ip" gorm:"type:varchar(64)"`
+	Detail    string    `json:"detail" gorm:"type:varchar(500)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_audit_created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// LoginAttempt 数据库版登录限流的计数器，Key 形如 user:alice 或 ip:10.0.0.1
+type LoginAttempt struct {
+	Key           string `gorm:"type:varchar(191);primaryKey"`
+	Failures      int    `gorm:"not null;default:0"`
+	LastFailureAt *time.Time
+	RetryAt       *time.Time
+	Locked        bool      `gorm:"not null;default:false"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// ReviewComment 面试复盘上的评论。ParentID 为空的是主评论，可以用 LineStart / LineEnd
+// 锚定复盘正文的行区间（从 1 开始，闭区间）；回复只有一层，回复的回复会挂到同一条主评论下。
+// OwnerID 冗余保存面试所属的求职者，便于按工作区统计未读；DeletedAt 非空表示已删除，保留记录以维持讨论结构
+type ReviewComment struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	InterviewID int64      `json:"interview_id" gorm:"not null;index:idx_comment_interview_id"`
+	OwnerID     int64      `json:"owner_id" gorm:"not null;index:idx_comment_owner_id"`
+	AuthorID    int64      `json:"author_id" gorm:"not null;index:idx_comment_author_id"`
+	ParentID    *int64     `json:"parent_id"`
+	LineStart   *int       `json:"line_start"`
+	LineEnd     *int       `json:"line_end"`
+	Body        string     `json:"body" gorm:"type:text"`
+	EditedAt    *time.Time `json:"edited_at"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (ReviewComment) TableName() string {
+	return "review_comments"
+}
+
+// ReviewCommentMention 评论中 @ 到的工作区成员
+type ReviewCommentMention struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	CommentID int64     `gorm:"not null;index:idx_mention_comment_id"`
+	UserID    int64     `gorm:"not null;index:idx_mention_user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (ReviewCommentMention) TableName() string {
+	return "review_comment_mentions"
+}
+
+// ReviewCommentRead 记录用户在某场面试下已读到的最大评论 ID
+type ReviewCommentRead struct {
+	UserID            int64     `gorm:"primaryKey"`
+	InterviewID       int64     `gorm:"primaryKey"`
+	LastReadCommentID int64     `gorm:"not null;default:0"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ReviewCommentRead) TableName() string {
+	return "review_comment_reads"
+}
+
+type CreateCommentRequest struct {
+	Body      string `json:"body" binding:"required,max=5000"`
+	ParentID  *int64 `json:"parent_id"`
+	LineStart *int   `json:"line_start" binding:"omitempty,min=1"`
+	LineEnd   *int   `json:"line_end" binding:"omitempty,min=1"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+type ReviewCommentResponse struct {
+	ReviewComment
+	AuthorUsername string   `json:"author_username"`
+	Mentions       []string `json:"mentions"`
+}
+
+// UnreadCommentCount 一场面试下他人发表的未读评论数，Mentions 为其中 @ 到当前用户的数量。
+// WorkspaceID 为 0 表示是自己的面试
+type UnreadCommentCount struct {
+	InterviewID int64 `json:"interview_id"`
+	OwnerID     int64 `json:"owner_id"`
+	WorkspaceID int64 `json:"workspace_id"`
+	Unread      int64 `json:"unread"`
+	Mentions    int64 `json:"mentions"`
+}
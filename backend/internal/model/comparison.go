@@ -0,0 +1,131 @@
+package model
+
+import "time"
+
+// OfferCriterion 用户自定义的 offer 评价维度，例如成长空间、通勤、团队氛围；Weight 为相对权重
+type OfferCriterion struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `json:"user_id" gorm:"not null;uniqueIndex:uk_criterion_user_name"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:uk_criterion_user_name"`
+	Weight    float64   `json:"weight" gorm:"type:decimal(6,2);not null;default:1"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (OfferCriterion) TableName() string {
+	return "offer_criteria"
+}
+
+// OfferScore 某个申请在某个评价维度上的打分，0-10 分
+type OfferScore struct {
+	ApplicationID int64     `json:"application_id" gorm:"primaryKey"`
+	CriterionID   int64     `json:"criterion_id" gorm:"primaryKey;index:idx_score_criterion_id"`
+	UserID        int64     `json:"user_id" gorm:"not null;index:idx_score_user_id"`
+	Score         float64   `json:"score" gorm:"type:decimal(4,2);not null"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (OfferScore) TableName() string {
+	return "offer_scores"
+}
+
+type CriterionRequest struct {
+	Name   string  `json:"name" binding:"required,max=50"`
+	Weight float64 `json:"weight" binding:"required,min=0,max=100"`
+}
+
+type ScoreItem struct {
+	CriterionID int64   `json:"criterion_id" binding:"required"`
+	Score       float64 `json:"score" binding:"min=0,max=10"`
+}
+
+// UpdateScoresRequest 整体替换申请的打分，没有列出的维度视为未打分
+type UpdateScoresRequest struct {
+	Scores []ScoreItem `json:"scores" binding:"dive"`
+}
+
+// OfferComparison 是 offer 横向对比的结果，每个申请一列，金额都已折算成 Currency
+type OfferComparison struct {
+	Currency string           `json:"currency"`
+	Criteria []OfferCriterion `json:"criteria"`
+	Offers   []OfferColumn    `json:"offers"`
+}
+
+// OfferColumn 是对比矩阵中的一列。OriginalCurrency 是薪资的原始币种，Rate 为 1 单位原币种折合多少 Currency；
+// ResponseDeadline 取自 offer 的答复截止时间；没有结构化薪资时 Compensation 和各项合计为空。WeightedScore 只按已打分的维度加权平均，全部未打分时为空
+type OfferColumn struct {
+	ApplicationID    int64                 `json:"application_id"`
+	CompanyName      string                `json:"company_name"`
+	JobTitle         string                `json:"job_title"`
+	CurrentStatus    string                `json:"current_status"`
+	City             string                `json:"city"`
+	OfferStatus      string                `json:"offer_status"`
+	ResponseDeadline *time.Time            `json:"response_deadline"`
+	OriginalCurrency string                `json:"original_currency,omitempty"`
+	Rate             float64               `json:"rate,omitempty"`
+	Compensation     *ComparedCompensation `json:"compensation"`
+	Totals           *CompensationTotals   `json:"totals"`
+	Scores           []ScoreItem           `json:"scores"`
+	WeightedScore    *float64              `json:"weighted_score"`
+}
+
+// ComparedCompensation 折算后的薪资组成
+type ComparedCompensation struct {
+	BaseMonthly       float64   `json:"base_monthly"`
+	BaseMonthlyMax    float64   `json:"base_monthly_max"`
+	Months            float64   `json:"months"`
+	SignOnBonus       float64   `json:"sign_on_bonus"`
+	AnnualBonusTarget float64   `json:"annual_bonus_target"`
+	StockGrant        float64   `json:"stock_grant"`
+	VestingSchedule   []float64 `json:"vesting_schedule"`
+	AllowancesMonthly float64   `json:"allowances_monthly"`
+	AnnualCash        float64   `json:"annual_cash"`
+}
+
+// CompensationTotals 前 1/2/4 年的累计总包，含签字费和按归属计划已归属的股票；Annualized 为稳定状态下的年化总包
+type CompensationTotals struct {
+	Year1      float64 `json:"year_1"`
+	Year2      float64 `json:"year_2"`
+	Year4      float64 `json:"year_4"`
+	Annualized float64 `json:"annualized"`
+}
+
+// Convert 按汇率折算成 currency 计价的薪资，rate 为 1 单位原币种折合多少 currency
+func (c *Compensation) Convert(currency string, rate float64) *Compensation {
+	converted := *c
+	converted.Currency = currency
+	converted.BaseMonthly = round2(c.BaseMonthly * rate)
+	converted.BaseMonthlyMax = round2(c.BaseMonthlyMax * rate)
+	converted.SignOnBonus = round2(c.SignOnBonus * rate)
+	converted.AnnualBonusTarget = round2(c.AnnualBonusTarget * rate)
+	converted.StockGrant = round2(c.StockGrant * rate)
+	converted.AllowancesMonthly = round2(c.AllowancesMonthly * rate)
+	converted.ComputeTotals()
+	return &converted
+}
+
+// Compared 返回对比矩阵使用的薪资组成和合计
+func (c *Compensation) Compared() (*ComparedCompensation, *CompensationTotals) {
+	fractions := c.VestingFractions()
+	if fractions == nil {
+		fractions = []float64{}
+	}
+	components := &ComparedCompensation{
+		BaseMonthly:       c.BaseMonthly,
+		BaseMonthlyMax:    c.BaseMonthlyMax,
+		Months:            c.Months,
+		SignOnBonus:       c.SignOnBonus,
+		AnnualBonusTarget: c.AnnualBonusTarget,
+		StockGrant:        c.StockGrant,
+		VestingSchedule:   fractions,
+		AllowancesMonthly: c.AllowancesMonthly,
+		AnnualCash:        round2(c.AnnualCash()),
+	}
+	totals := &CompensationTotals{
+		Year1:      round2(c.TotalOverYears(1)),
+		Year2:      round2(c.TotalOverYears(2)),
+		Year4:      round2(c.TotalOverYears(4)),
+		Annualized: c.AnnualizedTotal,
+	}
+	return components, totals
+}
@@ -0,0 +1,212 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/pkg/salary"
+)
+
+// Compensation 是申请的结构化薪资，每个申请至多一条。金额均以 Currency 计价；
+// BaseMonthlyMax 非 0 表示月薪是区间，合计时按下限 BaseMonthly 计算。
+// VestingSchedule 是逗号分隔的每年归属百分比，例如 "25,25,25,25"，为空表示按 VestingYears 平均归属。
+// Raw 保存解析来源的原始文本，手动填写时为空
+type Compensation struct {
+	ID                int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ApplicationID     int64     `json:"application_id" gorm:"not null;uniqueIndex:uk_compensation_application_id"`
+	UserID            int64     `json:"user_id" gorm:"not null;index:idx_compensation_user_id"`
+	Currency          string    `json:"currency" gorm:"type:varchar(3);not null;default:CNY"`
+	BaseMonthly       float64   `json:"base_monthly" gorm:"type:decimal(12,2);not null"`
+	BaseMonthlyMax    float64   `json:"base_monthly_max" gorm:"type:decimal(12,2);not null;default:0"`
+	Months            float64   `json:"months" gorm:"type:decimal(4,1);not null;default:12"`
+	SignOnBonus       float64   `json:"sign_on_bonus" gorm:"type:decimal(14,2);not null;default:0"`
+	AnnualBonusTarget float64   `json:"annual_bonus_target" gorm:"type:decimal(14,2);not null;default:0"`
+	StockGrant        float64   `json:"stock_grant" gorm:"type:decimal(14,2);not null;default:0"`
+	VestingYears      int       `json:"vesting_years" gorm:"not null;default:0"`
+	VestingSchedule   string    `json:"vesting_schedule" gorm:"type:varchar(100)"`
+	AllowancesMonthly float64   `json:"allowances_monthly" gorm:"type:decimal(12,2);not null;default:0"`
+	Raw               string    `json:"raw" gorm:"type:varchar(100)"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	FirstYearTotal    float64   `json:"first_year_total" gorm:"-"`
+	AnnualizedTotal   float64   `json:"annualized_total" gorm:"-"`
+}
+
+func (Compensation) TableName() string {
+	return "compensations"
+}
+
+// AfterFind 计算第一年和年化总包给 JSON 输出
+func (c *Compensation) AfterFind(tx *gorm.DB) error {
+	c.ComputeTotals()
+	return nil
+}
+
+func (c *Compensation) AfterSave(tx *gorm.DB) error {
+	c.ComputeTotals()
+	return nil
+}
+
+// ComputeTotals 第一年总包含签字费和第一年归属的股票；年化总包是稳定状态下的一年，
+// 不含签字费，股票按归属年限平摊
+func (c *Compensation) ComputeTotals() {
+	c.FirstYearTotal = round2(c.TotalOverYears(1))
+	c.AnnualizedTotal = round2(c.AnnualCash())
+	if years := c.vestingYears(); years > 0 {
+		c.AnnualizedTotal = round2(c.AnnualCash() + c.StockGrant/float64(years))
+	}
+}
+
+// AnnualCash 每年的现金收入：月薪 × 月数 + 年终奖目标 + 每月补贴 × 12
+func (c *Compensation) AnnualCash() float64 {
+	return c.BaseMonthly*c.Months + c.AnnualBonusTarget + c.AllowancesMonthly*12
+}
+
+// VestingFractions 返回每年的归属比例，之和为 1
+func (c *Compensation) VestingFractions() []float64 {
+	if c.StockGrant == 0 {
+		return nil
+	}
+	if c.VestingSchedule != "" {
+		if fractions, err := ParseVestingSchedule(c.VestingSchedule); err == nil {
+			return fractions
+		}
+	}
+	years := c.vestingYears()
+	fractions := make([]float64, years)
+	for i := range fractions {
+		fractions[i] = 1 / float64(years)
+	}
+	return fractions
+}
+
+// TotalOverYears 前 years 年的累计总包：每年现金 + 签字费 + 这些年内归属的股票
+func (c *Compensation) TotalOverYears(years int) float64 {
+	if years <= 0 {
+		return 0
+	}
+	total := c.AnnualCash()*float64(years) + c.SignOnBonus
+	for i, f := range c.VestingFractions() {
+		if i >= years {
+			break
+		}
+		total += c.StockGrant * f
+	}
+	return total
+}
+
+func (c *Compensation) vestingYears() int {
+	if c.StockGrant == 0 {
+		return 0
+	}
+	if c.VestingSchedule != "" {
+		if fractions, err := ParseVestingSchedule(c.VestingSchedule); err == nil {
+			return len(fractions)
+		}
+	}
+	if c.VestingYears > 0 {
+		return c.VestingYears
+	}
+	return salary.DefaultVestingYears
+}
+
+// ParseVestingSchedule 解析逗号分隔的每年归属百分比，要求合计为 100
+func ParseVestingSchedule(schedule string) ([]float64, error) {
+	parts := strings.Split(schedule, ",")
+	fractions := make([]float64, 0, len(parts))
+	var sum float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid vesting percentage %q", p)
+		}
+		sum += v
+		fractions = append(fractions, v/100)
+	}
+	if math.Abs(sum-100) > 0.01 {
+		return nil, fmt.Errorf("vesting percentages add up to %g, want 100", sum)
+	}
+	return fractions, nil
+}
+
+// ParseCompensation 把薪资文本解析成结构化薪资，ApplicationID 和 UserID 由调用方填写
+func ParseCompensation(text string) (*Compensation, error) {
+	p, err := salary.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	c := &Compensation{
+		Currency:          p.Currency,
+		BaseMonthly:       round2(p.BaseMonthly),
+		BaseMonthlyMax:    round2(p.BaseMonthlyMax),
+		Months:            p.Months,
+		SignOnBonus:       p.SignOnBonus,
+		AnnualBonusTarget: round2(p.AnnualBonusTarget),
+		StockGrant:        p.StockGrant,
+		VestingYears:      p.VestingYears,
+		AllowancesMonthly: p.AllowancesMonthly,
+		Raw:               text,
+	}
+	c.ComputeTotals()
+	return c, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// CompensationRequest 手动填写结构化薪资，整体替换已有记录
+type CompensationRequest struct {
+	Currency          string  `json:"currency" binding:"omitempty,len=3"`
+	BaseMonthly       float64 `json:"base_monthly" binding:"required,min=0"`
+	BaseMonthlyMax    float64 `json:"base_monthly_max" binding:"min=0"`
+	Months            float64 `json:"months" binding:"omitempty,min=12,max=24"`
+	SignOnBonus       float64 `json:"sign_on_bonus" binding:"min=0"`
+	AnnualBonusTarget float64 `json:"annual_bonus_target" binding:"min=0"`
+	StockGrant        float64 `json:"stock_grant" binding:"min=0"`
+	VestingYears      int     `json:"vesting_years" binding:"min=0,max=10"`
+	VestingSchedule   string  `json:"vesting_schedule" binding:"max=100"`
+	AllowancesMonthly float64 `json:"allowances_monthly" binding:"min=0"`
+}
+
+type ParseSalaryRequest struct {
+	Text string `json:"text" binding:"required,max=100"`
+}
+
+// Build 校验并转换成结构化薪资，月数默认 12，币种默认 CNY
+func (r *CompensationRequest) Build() (*Compensation, error) {
+	c := &Compensation{
+		Currency:          strings.ToUpper(r.Currency),
+		BaseMonthly:       r.BaseMonthly,
+		BaseMonthlyMax:    r.BaseMonthlyMax,
+		Months:            r.Months,
+		SignOnBonus:       r.SignOnBonus,
+		AnnualBonusTarget: r.AnnualBonusTarget,
+		StockGrant:        r.StockGrant,
+		VestingYears:      r.VestingYears,
+		VestingSchedule:   strings.ReplaceAll(r.VestingSchedule, " ", ""),
+		AllowancesMonthly: r.AllowancesMonthly,
+	}
+	if c.Currency == "" {
+		c.Currency = "CNY"
+	}
+	if c.Months == 0 {
+		c.Months = 12
+	}
+	if c.BaseMonthlyMax != 0 && c.BaseMonthlyMax < c.BaseMonthly {
+		return nil, fmt.Errorf("base_monthly_max must not be less than base_monthly")
+	}
+	if c.VestingSchedule != "" {
+		fractions, err := ParseVestingSchedule(c.VestingSchedule)
+		if err != nil {
+			return nil, err
+		}
+		c.VestingYears = len(fractions)
+	}
+	c.ComputeTotals()
+	return c, nil
+}
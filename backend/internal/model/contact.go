@@ -0,0 +1,116 @@
+package model
+
+import "time"
+
+// 联系记录类型
+const (
+	InteractionEmail   = "email"
+	InteractionCall    = "call"
+	InteractionMessage = "message"
+	InteractionMeeting = "meeting"
+	InteractionOther   = "other"
+)
+
+// Contact 招聘方联系人，例如 HR、猎头、面试官。LastContactedAt 冗余保存最近一次联系记录的时间，
+// 用于查找需要跟进的联系人；没有联系记录时为空
+type Contact struct {
+	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID          int64      `json:"user_id" gorm:"not null;index:idx_contact_user_id"`
+	Name            string     `json:"name" gorm:"type:varchar(50);not null"`
+	Role            string     `json:"role" gorm:"type:varchar(50)"`
+	Company         string     `json:"company" gorm:"type:varchar(100)"`
+	Email           string     `json:"email" gorm:"type:varchar(100)"`
+	Phone           string     `json:"phone" gorm:"type:varchar(30)"`
+	WeChat          string     `json:"wechat" gorm:"column:wechat;type:varchar(50)"`
+	LinkedIn        string     `json:"linkedin" gorm:"column:linkedin;type:varchar(200)"`
+	Notes           string     `json:"notes" gorm:"type:text"`
+	LastContactedAt *time.Time `json:"last_contacted_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Contact) TableName() string {
+	return "contacts"
+}
+
+// ContactApplication 联系人与申请的关联
+type ContactApplication struct {
+	ContactID     int64     `json:"contact_id" gorm:"primaryKey"`
+	ApplicationID int64     `json:"application_id" gorm:"primaryKey;index:idx_contact_app_application_id"`
+	UserID        int64     `json:"user_id" gorm:"not null;index:idx_contact_app_user_id"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ContactApplication) TableName() string {
+	return "contact_applications"
+}
+
+// ContactInterview 联系人与面试的关联，例如面试官
+type ContactInterview struct {
+	ContactID   int64     `json:"contact_id" gorm:"primaryKey"`
+	InterviewID int64     `json:"interview_id" gorm:"primaryKey;index:idx_contact_interview_interview_id"`
+	UserID      int64     `json:"user_id" gorm:"not null;index:idx_contact_interview_user_id"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ContactInterview) TableName() string {
+	return "contact_interviews"
+}
+
+// ContactInteraction 与联系人的一次往来，例如邮件、电话、微信消息
+type ContactInteraction struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ContactID  int64     `json:"contact_id" gorm:"not null;index:idx_interaction_contact_id"`
+	UserID     int64     `json:"user_id" gorm:"not null;index:idx_interaction_user_id"`
+	Kind       string    `json:"kind" gorm:"type:varchar(20);not null"`
+	Summary    string    `json:"summary" gorm:"type:text"`
+	OccurredAt time.Time `json:"occurred_at" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ContactInteraction) TableName() string {
+	return "contact_interactions"
+}
+
+type ContactRequest struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Role     string `json:"role" binding:"max=50"`
+	Company  string `json:"company" binding:"max=100"`
+	Email    string `json:"email" binding:"omitempty,email,max=100"`
+	Phone    string `json:"phone" binding:"max=30"`
+	WeChat   string `json:"wechat" binding:"max=50"`
+	LinkedIn string `json:"linkedin" binding:"max=200"`
+	Notes    string `json:"notes"`
+}
+
+type CreateInteractionRequest struct {
+	Kind       string     `json:"kind" binding:"required,oneof=email call message meeting other"`
+	Summary    string     `json:"summary"`
+	OccurredAt *time.Time `json:"occurred_at"`
+}
+
+// ContactApplicationRef 联系人详情中关联的申请摘要
+type ContactApplicationRef struct {
+	ID            int64  `json:"id"`
+	CompanyName   string `json:"company_name"`
+	JobTitle      string `json:"job_title"`
+	CurrentStatus string `json:"current_status"`
+}
+
+// ContactInterviewRef 联系人详情中关联的面试摘要
+type ContactInterviewRef struct {
+	ID            int64     `json:"id"`
+	ApplicationID int64     `json:"application_id"`
+	CompanyName   string    `json:"company_name"`
+	RoundName     string    `json:"round_name"`
+	StartTime     time.Time `json:"start_time"`
+	Status        string    `json:"status"`
+}
+
+// ContactDetail 联系人详情，联系记录按时间倒序
+type ContactDetail struct {
+	Contact
+	Applications []ContactApplicationRef `json:"applications"`
+	Interviews   []ContactInterviewRef   `json:"interviews"`
+	Interactions []ContactInteraction    `json:"interactions"`
+}
@@ -2,18 +2,26 @@ package model
 
 import "time"
 
+// 面试状态
+const (
+	InterviewStatusScheduled = "SCHEDULED"
+	InterviewStatusFinished  = "FINISHED"
+	InterviewStatusCancelled = "CANCELLED"
+)
+
 type Interview struct {
-	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	ApplicationID int64     `json:"application_id" gorm:"not null;index:idx_app_id"`
-	RoundName     string    `json:"round_name" gorm:"type:varchar(50);not null"`
-	StartTime     time.Time `json:"start_time" gorm:"not null;index:idx_start_time"`
-	EndTime       time.Time `json:"end_time" gorm:"not null"`
-	Status        string    `json:"status" gorm:"type:varchar(20);default:SCHEDULED"`
-	MeetingLink   string    `json:"meeting_link" gorm:"type:varchar(500)"`
-	Notes         string    `json:"notes" gorm:"type:text"`
-	ReviewContent string    `json:"review_content" gorm:"type:text"`
-	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID            int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        int64        `json:"user_id" gorm:"not null;index:idx_interview_user_id"`
+	ApplicationID int64        `json:"application_id" gorm:"not null;index:idx_app_id"`
+	RoundName     string       `json:"round_name" gorm:"type:varchar(50);not null"`
+	StartTime     time.Time    `json:"start_time" gorm:"not null;index:idx_start_time"`
+	EndTime       time.Time    `json:"end_time" gorm:"not null"`
+	Status        string       `json:"status" gorm:"type:varchar(20);default:SCHEDULED"`
+	MeetingLink   string       `json:"meeting_link" gorm:"type:varchar(500)"`
+	Notes         string       `json:"notes" gorm:"type:text"`
+	ReviewContent string       `json:"review_content" gorm:"type:text"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
 	Application   *Application `json:"application,omitempty" gorm:"foreignKey:ApplicationID"`
 }
 
@@ -45,3 +53,9 @@ type UpdateInterviewRequest struct {
 type UpdateReviewRequest struct {
 	ReviewContent string `json:"review_content"`
 }
+
+// InterviewConflict 描述一场面试与其他面试的时间冲突
+type InterviewConflict struct {
+	Interview     Interview   `json:"interview"`
+	ConflictsWith []Interview `json:"conflicts_with"`
+}
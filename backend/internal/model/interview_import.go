@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// ImportedInterview 是从 .ics 邀请解析出的面试预览，用户确认后才会写入
+type ImportedInterview struct {
+	UID           string      `json:"uid"`
+	CompanyName   string      `json:"company_name"`
+	RoundName     string      `json:"round_name"`
+	StartTime     time.Time   `json:"start_time"`
+	EndTime       time.Time   `json:"end_time"`
+	MeetingLink   string      `json:"meeting_link"`
+	Location      string      `json:"location"`
+	Notes         string      `json:"notes"`
+	Organizer     string      `json:"organizer"`
+	ApplicationID int64       `json:"application_id"` // 0 表示确认时新建申请
+	MatchedBy     string      `json:"matched_by"`     // domain / company，未匹配为空
+	Conflicts     []Interview `json:"conflicts"`
+}
+
+type ConfirmImportRequest struct {
+	Items []ConfirmImportItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type ConfirmImportItem struct {
+	ApplicationID int64  `json:"application_id"`
+	CompanyName   string `json:"company_name"`
+	RoundName     string `json:"round_name" binding:"required,max=50"`
+	StartTime     string `json:"start_time" binding:"required"`
+	EndTime       string `json:"end_time" binding:"required"`
+	MeetingLink   string `json:"meeting_link"`
+	Notes         string `json:"notes"`
+}
@@ -0,0 +1,115 @@
+package model
+
+import "time"
+
+// Offer 状态
+const (
+	OfferStatusPending   = "PENDING"
+	OfferStatusAccepted  = "ACCEPTED"
+	OfferStatusDeclined  = "DECLINED"
+	OfferStatusRescinded = "RESCINDED"
+)
+
+var offerStatuses = []string{OfferStatusPending, OfferStatusAccepted, OfferStatusDeclined, OfferStatusRescinded}
+
+func IsValidOfferStatus(status string) bool {
+	for _, s := range offerStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// 谈判记录类型：ask 是自己提出的要求，counter 是公司的还价
+const (
+	NegotiationAsk     = "ask"
+	NegotiationCounter = "counter"
+)
+
+// Offer 是申请收到的 offer，每个申请至多一个。ExpiresAt 是答复截止时间，待答复的 offer 会在截止前提醒；
+// DecidedAt 记录离开 PENDING 状态的时间
+type Offer struct {
+	ID            int64              `json:"id" gorm:"primaryKey;autoIncrement"`
+	ApplicationID int64              `json:"application_id" gorm:"not null;uniqueIndex:uk_offer_application_id"`
+	UserID        int64              `json:"user_id" gorm:"not null;index:idx_offer_user_id"`
+	Status        string             `json:"status" gorm:"type:varchar(20);not null;default:PENDING"`
+	ReceivedAt    *time.Time         `json:"received_at"`
+	ExpiresAt     *time.Time         `json:"expires_at" gorm:"index:idx_offer_expires_at"`
+	DecidedAt     *time.Time         `json:"decided_at"`
+	Notes         string             `json:"notes" gorm:"type:text"`
+	CreatedAt     time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+	Negotiations  []OfferNegotiation `json:"negotiations,omitempty" gorm:"foreignKey:OfferID"`
+	Application   *Application       `json:"application,omitempty" gorm:"foreignKey:ApplicationID"`
+}
+
+func (Offer) TableName() string {
+	return "offers"
+}
+
+// OfferNegotiation 谈判过程中的一次要价或还价。Terms 是简短的条件，例如 "35k*16"，能解析时附带年化总包
+type OfferNegotiation struct {
+	ID              int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	OfferID         int64     `json:"offer_id" gorm:"not null;index:idx_negotiation_offer_id"`
+	UserID          int64     `json:"user_id" gorm:"not null;index:idx_negotiation_user_id"`
+	Kind            string    `json:"kind" gorm:"type:varchar(20);not null"`
+	Terms           string    `json:"terms" gorm:"type:varchar(100)"`
+	Note            string    `json:"note" gorm:"type:text"`
+	OccurredAt      time.Time `json:"occurred_at" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	AnnualizedTotal *float64  `json:"annualized_total" gorm:"-"`
+}
+
+func (OfferNegotiation) TableName() string {
+	return "offer_negotiations"
+}
+
+// ParseTerms 尝试把 Terms 解析成年化总包
+func (n *OfferNegotiation) ParseTerms() {
+	n.AnnualizedTotal = nil
+	if n.Terms == "" {
+		return
+	}
+	if c, err := ParseCompensation(n.Terms); err == nil {
+		n.AnnualizedTotal = &c.AnnualizedTotal
+	}
+}
+
+// OfferReminder 记录 offer 在某个提前量、某个渠道上的截止提醒，唯一键包含截止时间快照，
+// 截止时间修改后会生成新的提醒
+type OfferReminder struct {
+	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	OfferID       int64      `json:"offer_id" gorm:"not null;uniqueIndex:uniq_offer_reminder,priority:1"`
+	UserID        int64      `json:"user_id" gorm:"not null"`
+	Channel       string     `json:"channel" gorm:"type:varchar(20);not null;uniqueIndex:uniq_offer_reminder,priority:2"`
+	OffsetMinutes int        `json:"offset_minutes" gorm:"not null;uniqueIndex:uniq_offer_reminder,priority:3"`
+	ExpiresAt     time.Time  `json:"expires_at" gorm:"not null;uniqueIndex:uniq_offer_reminder,priority:4"`
+	FireAt        time.Time  `json:"fire_at" gorm:"not null;index:idx_offer_reminder_fire_at"`
+	Status        string     `json:"status" gorm:"type:varchar(20);default:PENDING;index:idx_offer_reminder_status"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	LastError     string     `json:"last_error" gorm:"type:varchar(500)"`
+	LockedUntil   *time.Time `json:"-"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (OfferReminder) TableName() string {
+	return "offer_reminders"
+}
+
+// SaveOfferRequest 创建或修改申请的 offer，未填写的字段保持不变
+type SaveOfferRequest struct {
+	Status     string     `json:"status"`
+	ReceivedAt *time.Time `json:"received_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Notes      *string    `json:"notes"`
+}
+
+type CreateNegotiationRequest struct {
+	Kind       string     `json:"kind" binding:"required,oneof=ask counter"`
+	Terms      string     `json:"terms" binding:"max=100"`
+	Note       string     `json:"note"`
+	OccurredAt *time.Time `json:"occurred_at"`
+}
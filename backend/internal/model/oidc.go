@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// OIDCLoginState 跟踪一次单点登录：跳转身份提供方前写入 state、nonce 和 PKCE verifier；
+// 回调校验通过后写入一次性的 handoff code，前端用它换取正式 token，避免 token 出现在地址栏
+type OIDCLoginState struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement"`
+	StateHash     string `gorm:"type:varchar(64);not null;uniqueIndex:uniq_oidc_state_hash"`
+	Nonce         string `gorm:"type:varchar(64);not null"`
+	CodeVerifier  string `gorm:"type:varchar(128);not null"`
+	RedirectPath  string `gorm:"type:varchar(255)"`
+	CallbackAt    *time.Time
+	UserID        int64  `gorm:"default:0"`
+	HandoffHash   string `gorm:"type:varchar(64);index:idx_oidc_handoff_hash"`
+	HandoffUsedAt *time.Time
+	ExpiresAt     time.Time `gorm:"not null;index:idx_oidc_expires_at"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// OIDCConfigResponse 告诉前端是否显示单点登录按钮
+type OIDCConfigResponse struct {
+	Enabled    bool   `json:"enabled"`
+	ButtonText string `json:"button_text"`
+}
+
+type OIDCExchangeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
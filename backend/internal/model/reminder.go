@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// 提醒状态
+const (
+	ReminderStatusPending = "PENDING"
+	ReminderStatusSending = "SENDING"
+	ReminderStatusSent    = "SENT"
+	ReminderStatusFailed  = "FAILED"
+	ReminderStatusSkipped = "SKIPPED"
+)
+
+// InterviewReminder 记录某场面试在某个提前量、某个渠道上的提醒投递状态。
+// 唯一键包含面试开始时间快照，面试改期后会生成新的提醒而不是沿用旧记录
+type InterviewReminder struct {
+	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	InterviewID   int64      `json:"interview_id" gorm:"not null;uniqueIndex:uniq_interview_reminder,priority:1"`
+	UserID        int64      `json:"user_id" gorm:"not null"`
+	Channel       string     `json:"channel" gorm:"type:varchar(20);not null;uniqueIndex:uniq_interview_reminder,priority:2"`
+	OffsetMinutes int        `json:"offset_minutes" gorm:"not null;uniqueIndex:uniq_interview_reminder,priority:3"`
+	StartTime     time.Time  `json:"start_time" gorm:"not null;uniqueIndex:uniq_interview_reminder,priority:4"`
+	FireAt        time.Time  `json:"fire_at" gorm:"not null;index:idx_reminder_fire_at"`
+	Status        string     `json:"status" gorm:"type:varchar(20);default:PENDING;index:idx_reminder_status"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
+	LastError     string     `json:"last_error" gorm:"type:varchar(500)"`
+	LockedUntil   *time.Time `json:"-"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (InterviewReminder) TableName() string {
+	return "interview_reminders"
+}
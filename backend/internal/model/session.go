@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// 会话吊销原因
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedReuseDetected = "refresh_token_reuse"
+	SessionRevokedByUser        = "revoked_by_user"
+	SessionRevokedOthers        = "logout_others"
+	SessionRevokedPassword      = "password_changed"
+	SessionRevokedDisabled      = "account_disabled"
+)
+
+// AuthSession 对应一次登录。同一会话内不断轮换的 refresh token 属于同一家族，
+// 会话被吊销后家族内所有 refresh token 和关联的 access token 一并失效
+type AuthSession struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int64      `json:"user_id" gorm:"not null;index:idx_session_user_id"`
+	Device       string     `json:"device" gorm:"type:varchar(100)"`
+	UserAgent    string     `json:"user_agent" gorm:"type:varchar(255)"`
+	IP           string     `json:"ip" gorm:"type:varchar(64)"`
+	LastSeenAt   time.Time  `json:"last_seen_at"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	RevokeReason string     `json:"revoke_reason" gorm:"type:varchar(50)"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+// RefreshToken 只保存令牌摘要；UsedAt 非空表示已被轮换，再次出现即视为重放
+type RefreshToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SessionID int64      `json:"session_id" gorm:"not null;index:idx_refresh_session_id"`
+	UserID    int64      `json:"user_id" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:uniq_refresh_token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse 刷新接口的返回，ExpiresIn 为 access token 剩余秒数
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// SessionResponse 会话列表项，Current 标记发起请求的会话
+type SessionResponse struct {
+	ID         int64     `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
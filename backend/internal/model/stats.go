@@ -0,0 +1,34 @@
+package model
+
+// Stats 是统计页使用的汇总数据，比率均为 0-1 之间的小数
+type Stats struct {
+	TotalApplications int64         `json:"total_applications"`
+	StatusCounts      []StatusCount `json:"status_counts"`
+	// OfferCount 曾经拿到 Offer 的申请数，包括之后拒绝或被撤回的
+	OfferCount           int64         `json:"offer_count"`
+	OfferRate            float64       `json:"offer_rate"`
+	TotalInterviews      int64         `json:"total_interviews"`
+	FinishedInterviews   int64         `json:"finished_interviews"`
+	ReviewedInterviews   int64         `json:"reviewed_interviews"`
+	ReviewCompletionRate float64       `json:"review_completion_rate"`
+	InterviewsByWeek     []PeriodCount `json:"interviews_by_week"`
+	InterviewsByMonth    []PeriodCount `json:"interviews_by_month"`
+	RoundDistribution    []RoundCount  `json:"round_distribution"`
+	AvgDaysToOffer       *float64      `json:"avg_days_to_offer"`
+}
+
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// PeriodCount 的 Period 为周一日期（2006-01-02）或月份（2006-01）
+type PeriodCount struct {
+	Period string `json:"period"`
+	Count  int64  `json:"count"`
+}
+
+type RoundCount struct {
+	RoundName string `json:"round_name"`
+	Count     int64  `json:"count"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// RecoveryCode 两步验证的一次性恢复码，只保存摘要
+type RecoveryCode struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"user_id" gorm:"not null;index:idx_recovery_user_id"`
+	CodeHash  string     `json:"-" gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// TwoFactorStatus 当前用户的两步验证状态
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetupResponse 开始绑定时返回密钥和 otpauth 链接，确认前不会生效
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest 关闭两步验证需要密码和验证码（或恢复码）
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse 恢复码明文只在生成时返回一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallengeResponse 密码正确但需要两步验证时返回，凭 challenge token 和验证码换取正式 token
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int64  `json:"expires_in"`
+}
+
+// LoginTwoFactorRequest 的 Code 可以是验证器应用上的 6 位验证码，也可以是恢复码
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
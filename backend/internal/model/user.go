@@ -2,12 +2,31 @@ package model
 
 import "time"
 
+// 用户角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// User 的 CalendarTokenHash 是日历订阅密钥的摘要，明文只在生成时返回一次。
+// TOTPSecret 在确认绑定前即写入，TOTPEnabled 为 true 后登录才要求验证码。
+// OIDCIssuer / OIDCSubject 记录关联的企业身份，未关联时为 NULL 以免触发唯一索引冲突。
+// DisabledAt 非空表示账号被管理员停用
 type User struct {
-	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	Username  string    `json:"username" gorm:"type:varchar(50);uniqueIndex;not null"`
-	Password  string    `json:"-" gorm:"type:varchar(255);not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username          string     `json:"username" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Password          string     `json:"-" gorm:"type:varchar(255);not null"`
+	Email             string     `json:"email" gorm:"type:varchar(255)"`
+	CalendarTokenHash string     `json:"-" gorm:"type:varchar(64);index:idx_calendar_token_hash"`
+	TOTPSecret        string     `json:"-" gorm:"column:totp_secret;type:varchar(64)"`
+	TOTPEnabled       bool       `json:"-" gorm:"column:totp_enabled;default:false"`
+	TOTPLastCounter   int64      `json:"-" gorm:"column:totp_last_counter;default:0"`
+	OIDCIssuer        *string    `json:"-" gorm:"column:oidc_issuer;type:varchar(191);uniqueIndex:uniq_user_oidc,priority:1"`
+	OIDCSubject       *string    `json:"-" gorm:"column:oidc_subject;type:varchar(191);uniqueIndex:uniq_user_oidc,priority:2"`
+	Role              string     `json:"role" gorm:"type:varchar(20);not null;default:user"`
+	DisabledAt        *time.Time `json:"disabled_at"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 func (User) TableName() string {
@@ -17,6 +36,44 @@ func (User) TableName() string {
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
 	Password string `json:"password" binding:"required,min=6"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+type UpdateProfileRequest struct {
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// DeleteAccountRequest 注销账号前需要再次输入密码确认
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// PasswordResetToken 找回密码的一次性令牌，只保存摘要
+type PasswordResetToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"user_id" gorm:"not null;index:idx_reset_user_id"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex:uniq_reset_token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
 }
 
 type LoginRequest struct {
@@ -25,12 +82,21 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int64        `json:"expires_in"`
+	User         UserResponse `json:"user"`
 }
 
 type UserResponse struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               int64     `json:"id"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
 }
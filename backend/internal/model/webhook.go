@@ -0,0 +1,114 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 出站回调事件
+const (
+	EventApplicationCreated       = "application.created"
+	EventApplicationStatusChanged = "application.status_changed"
+	EventInterviewScheduled       = "interview.scheduled"
+	EventInterviewRescheduled     = "interview.rescheduled"
+	EventInterviewCancelled       = "interview.cancelled"
+	EventInterviewReviewWritten   = "interview.review_written"
+	EventWebhookTest              = "webhook.test"
+)
+
+// WebhookEvents 是可以订阅的事件列表
+var WebhookEvents = []string{
+	EventApplicationCreated,
+	EventApplicationStatusChanged,
+	EventInterviewScheduled,
+	EventInterviewRescheduled,
+	EventInterviewCancelled,
+	EventInterviewReviewWritten,
+}
+
+// 投递状态
+const (
+	DeliveryStatusPending   = "PENDING"
+	DeliveryStatusSending   = "SENDING"
+	DeliveryStatusSucceeded = "SUCCEEDED"
+	DeliveryStatusFailed    = "FAILED"
+)
+
+type WebhookSubscription struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      int64     `json:"user_id" gorm:"not null;index:idx_webhook_user_id"`
+	URL         string    `json:"url" gorm:"type:varchar(500);not null"`
+	Secret      string    `json:"-" gorm:"type:varchar(100);not null"`
+	Events      string    `json:"-" gorm:"type:varchar(500);not null"` // 逗号分隔，* 表示全部
+	Description string    `json:"description" gorm:"type:varchar(200)"`
+	Active      bool      `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	EventList   []string  `json:"events" gorm:"-"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// AfterFind 把逗号分隔的事件展开给 JSON 输出
+func (s *WebhookSubscription) AfterFind(tx *gorm.DB) error {
+	s.EventList = strings.Split(s.Events, ",")
+	return nil
+}
+
+// Subscribes 判断是否订阅了该事件，测试事件总是投递
+func (s *WebhookSubscription) Subscribes(event string) bool {
+	if event == EventWebhookTest {
+		return true
+	}
+	for _, e := range strings.Split(s.Events, ",") {
+		if e == "*" || e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID int64      `json:"subscription_id" gorm:"not null;index:idx_delivery_subscription_id"`
+	UserID         int64      `json:"user_id" gorm:"not null"`
+	EventID        string     `json:"event_id" gorm:"type:varchar(64);not null"`
+	Event          string     `json:"event" gorm:"type:varchar(50);not null"`
+	Payload        string     `json:"payload" gorm:"type:text"`
+	Status         string     `json:"status" gorm:"type:varchar(20);default:PENDING;index:idx_delivery_status_next,priority:1"`
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"index:idx_delivery_status_next,priority:2"`
+	LockedUntil    *time.Time `json:"-"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error" gorm:"type:varchar(500)"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	Description string   `json:"description"`
+}
+
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url" binding:"omitempty,url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Active      *bool    `json:"active"`
+}
+
+// CreateWebhookResponse 只在创建时返回签名密钥
+type CreateWebhookResponse struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// 工作区成员权限。薪资和面试复盘正文只有求职者本人可以修改，不设对应权限
+const (
+	PermViewPipeline   = "view_pipeline"
+	PermEditPipeline   = "edit_pipeline"
+	PermViewReviews    = "view_reviews"
+	PermCommentReviews = "comment_reviews"
+)
+
+var AllWorkspacePermissions = []string{PermViewPipeline, PermEditPipeline, PermViewReviews, PermCommentReviews}
+
+// 成员状态：邀请待处理、已接受、被拒绝、被求职者撤销、教练主动退出
+const (
+	MemberPending  = "pending"
+	MemberAccepted = "accepted"
+	MemberDeclined = "declined"
+	MemberRevoked  = "revoked"
+	MemberLeft     = "left"
+)
+
+// Workspace 每个求职者最多一个，首次邀请教练时创建
+type Workspace struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	OwnerID   int64     `json:"owner_id" gorm:"not null;uniqueIndex:uniq_workspace_owner"`
+	Name      string    `json:"name" gorm:"type:varchar(100)"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceMember 同时承担邀请记录：被拒绝或撤销后再次邀请会复用同一行。
+// Permissions 以逗号分隔保存
+type WorkspaceMember struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	WorkspaceID int64      `json:"workspace_id" gorm:"not null;uniqueIndex:uniq_workspace_member,priority:1"`
+	UserID      int64      `json:"user_id" gorm:"not null;uniqueIndex:uniq_workspace_member,priority:2;index:idx_member_user_id"`
+	Permissions string     `json:"-" gorm:"type:varchar(255);not null"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null"`
+	InvitedBy   int64      `json:"invited_by"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+func (m *WorkspaceMember) PermissionList() []string {
+	if m.Permissions == "" {
+		return []string{}
+	}
+	return strings.Split(m.Permissions, ",")
+}
+
+func (m *WorkspaceMember) Can(permission string) bool {
+	for _, p := range m.PermissionList() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinPermissions 去重并按固定顺序拼接，编辑权限隐含查看权限
+func JoinPermissions(permissions []string) string {
+	set := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		set[p] = true
+	}
+	if set[PermEditPipeline] {
+		set[PermViewPipeline] = true
+	}
+	if set[PermCommentReviews] {
+		set[PermViewReviews] = true
+	}
+
+	var ordered []string
+	for _, p := range AllWorkspacePermissions {
+		if set[p] {
+			ordered = append(ordered, p)
+		}
+	}
+	return strings.Join(ordered, ",")
+}
+
+type InviteMemberRequest struct {
+	Username    string   `json:"username" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,oneof=view_pipeline edit_pipeline view_reviews comment_reviews"`
+}
+
+type UpdateMemberRequest struct {
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,oneof=view_pipeline edit_pipeline view_reviews comment_reviews"`
+}
+
+// WorkspaceMemberResponse 求职者看到的成员（含待处理邀请）
+type WorkspaceMemberResponse struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Username    string     `json:"username"`
+	Permissions []string   `json:"permissions"`
+	Status      string     `json:"status"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type WorkspaceResponse struct {
+	Workspace *Workspace                `json:"workspace"`
+	Members   []WorkspaceMemberResponse `json:"members"`
+}
+
+// MembershipResponse 教练看到的邀请和已加入的工作区
+type MembershipResponse struct {
+	ID            int64      `json:"id"`
+	WorkspaceID   int64      `json:"workspace_id"`
+	WorkspaceName string     `json:"workspace_name"`
+	OwnerID       int64      `json:"owner_id"`
+	OwnerUsername string     `json:"owner_username"`
+	Permissions   []string   `json:"permissions"`
+	Status        string     `json:"status"`
+	RespondedAt   *time.Time `json:"responded_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
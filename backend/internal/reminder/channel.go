@@ -0,0 +1,210 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"offermatrix/internal/model"
+	"offermatrix/pkg/hmacsig"
+	"offermatrix/pkg/mailer"
+)
+
+// ErrNoRecipient 表示该用户在此渠道上没有可投递的地址，提醒会被跳过而不是重试
+var ErrNoRecipient = errors.New("reminder: no recipient for channel")
+
+// Notification 是一条待投递的提醒：Offer 不为空时是 offer 截止提醒，否则是面试提醒
+type Notification struct {
+	Interview model.Interview
+	Offer     *model.Offer
+	User      model.User
+	Offset    time.Duration
+}
+
+// Channel 是提醒的投递渠道
+type Channel interface {
+	Name() string
+	Deliver(ctx context.Context, n Notification) error
+}
+
+// EmailChannel 通过邮件发送提醒，收件人为用户资料中的邮箱
+type EmailChannel struct {
+	sender mailer.Sender
+}
+
+func NewEmailChannel(sender mailer.Sender) *EmailChannel {
+	return &EmailChannel{sender: sender}
+}
+
+func (c *EmailChannel) Name() string {
+	return "email"
+}
+
+func (c *EmailChannel) Deliver(ctx context.Context, n Notification) error {
+	if n.User.Email == "" {
+		return ErrNoRecipient
+	}
+	return c.sender.Send(n.User.Email, subject(n), body(n))
+}
+
+// WebhookChannel 以 JSON POST 到固定地址，配置了 secret 时附带 HMAC-SHA256 签名
+type WebhookChannel struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookChannel(url, secret string) *WebhookChannel {
+	return &WebhookChannel{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+type webhookPayload struct {
+	Event         string    `json:"event"`
+	UserID        int64     `json:"user_id"`
+	Username      string    `json:"username"`
+	InterviewID   int64     `json:"interview_id"`
+	CompanyName   string    `json:"company_name"`
+	RoundName     string    `json:"round_name"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	MeetingLink   string    `json:"meeting_link"`
+	OffsetMinutes int       `json:"offset_minutes"`
+	Text          string    `json:"text"`
+}
+
+// offerWebhookPayload 是 offer 截止提醒的回调内容
+type offerWebhookPayload struct {
+	Event         string    `json:"event"`
+	UserID        int64     `json:"user_id"`
+	Username      string    `json:"username"`
+	OfferID       int64     `json:"offer_id"`
+	ApplicationID int64     `json:"application_id"`
+	CompanyName   string    `json:"company_name"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	OffsetMinutes int       `json:"offset_minutes"`
+	Text          string    `json:"text"`
+}
+
+func (c *WebhookChannel) Deliver(ctx context.Context, n Notification) error {
+	payload, err := c.payload(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		req.Header.Set("X-OfferMatrix-Signature", hmacsig.Sign(c.secret, payload))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *WebhookChannel) payload(n Notification) ([]byte, error) {
+	if n.Offer != nil {
+		return json.Marshal(offerWebhookPayload{
+			Event:         "offer.expiring",
+			UserID:        n.User.ID,
+			Username:      n.User.Username,
+			OfferID:       n.Offer.ID,
+			ApplicationID: n.Offer.ApplicationID,
+			CompanyName:   offerCompanyName(n.Offer),
+			ExpiresAt:     *n.Offer.ExpiresAt,
+			OffsetMinutes: int(n.Offset / time.Minute),
+			Text:          subject(n),
+		})
+	}
+	return json.Marshal(webhookPayload{
+		Event:         "interview.reminder",
+		UserID:        n.User.ID,
+		Username:      n.User.Username,
+		InterviewID:   n.Interview.ID,
+		CompanyName:   companyName(n.Interview),
+		RoundName:     n.Interview.RoundName,
+		StartTime:     n.Interview.StartTime,
+		EndTime:       n.Interview.EndTime,
+		MeetingLink:   n.Interview.MeetingLink,
+		OffsetMinutes: int(n.Offset / time.Minute),
+		Text:          subject(n),
+	})
+}
+
+func offerCompanyName(offer *model.Offer) string {
+	if offer.Application != nil {
+		return offer.Application.CompanyName
+	}
+	return ""
+}
+
+func companyName(interview model.Interview) string {
+	if interview.Application != nil {
+		return interview.Application.CompanyName
+	}
+	return ""
+}
+
+func subject(n Notification) string {
+	if n.Offer != nil {
+		return fmt.Sprintf("Offer 截止提醒：%s 的 offer 将于 %s 截止答复",
+			offerCompanyName(n.Offer), n.Offer.ExpiresAt.Format("01-02 15:04"))
+	}
+	return fmt.Sprintf("面试提醒：%s %s 将于 %s 开始",
+		companyName(n.Interview), n.Interview.RoundName, n.Interview.StartTime.Format("01-02 15:04"))
+}
+
+func body(n Notification) string {
+	if n.Offer != nil {
+		return offerBody(n)
+	}
+	lines := []string{
+		fmt.Sprintf("%s，你好：", n.User.Username),
+		"",
+		fmt.Sprintf("公司：%s", companyName(n.Interview)),
+		fmt.Sprintf("轮次：%s", n.Interview.RoundName),
+		fmt.Sprintf("时间：%s - %s",
+			n.Interview.StartTime.Format("2006-01-02 15:04"), n.Interview.EndTime.Format("15:04")),
+	}
+	if n.Interview.MeetingLink != "" {
+		lines = append(lines, fmt.Sprintf("会议链接：%s", n.Interview.MeetingLink))
+	}
+	lines = append(lines, "", "祝你 Offer 拿到手软！")
+	return strings.Join(lines, "\n")
+}
+
+func offerBody(n Notification) string {
+	lines := []string{
+		fmt.Sprintf("%s，你好：", n.User.Username),
+		"",
+		fmt.Sprintf("公司：%s", offerCompanyName(n.Offer)),
+		fmt.Sprintf("答复截止：%s", n.Offer.ExpiresAt.Format("2006-01-02 15:04")),
+	}
+	if n.Offer.Application != nil && n.Offer.Application.JobTitle != "" {
+		lines = append(lines, fmt.Sprintf("职位：%s", n.Offer.Application.JobTitle))
+	}
+	lines = append(lines, "", "请在截止前做出决定，需要更多时间可以尽早与 HR 沟通。")
+	return strings.Join(lines, "\n")
+}
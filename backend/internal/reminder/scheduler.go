@@ -0,0 +1,329 @@
+// Package reminder 在服务进程内定时扫描即将开始的面试和即将截止的 offer 并投递提醒
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"offermatrix/internal/config"
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+const (
+	maxAttempts = 3
+	claimLease  = 5 * time.Minute
+	batchSize   = 100
+)
+
+type Scheduler struct {
+	reminders  *repository.ReminderRepository
+	interviews *repository.InterviewRepository
+	offers     *repository.OfferRepository
+	users      *repository.UserRepository
+	channels   map[string]Channel
+	offsets    []time.Duration
+	// offerOffsets 是 offer 截止前的提醒提前量，为空时不提醒 offer
+	offerOffsets []time.Duration
+	interval     time.Duration
+}
+
+func NewScheduler(cfg config.ReminderConfig, channels ...Channel) (*Scheduler, error) {
+	offsets, err := parseOffsets(cfg.Offsets)
+	if err != nil {
+		return nil, err
+	}
+	offerOffsets, err := parseOffsets(cfg.OfferOffsets)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(cfg.ScanIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	byName := make(map[string]Channel, len(channels))
+	for _, ch := range channels {
+		byName[ch.Name()] = ch
+	}
+
+	return &Scheduler{
+		reminders:    repository.NewReminderRepository(),
+		interviews:   repository.NewInterviewRepository(),
+		offers:       repository.NewOfferRepository(),
+		users:        repository.NewUserRepository(),
+		channels:     byName,
+		offsets:      offsets,
+		offerOffsets: offerOffsets,
+		interval:     interval,
+	}, nil
+}
+
+// parseOffsets 解析提前量并从小到大排序
+func parseOffsets(values []string) ([]time.Duration, error) {
+	offsets := make([]time.Duration, 0, len(values))
+	for _, s := range values {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid reminder offset %q", s)
+		}
+		offsets = append(offsets, d)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
+
+// Run 周期性扫描直到 ctx 取消
+func (s *Scheduler) Run(ctx context.Context) {
+	if (len(s.offsets) == 0 && len(s.offerOffsets) == 0) || len(s.channels) == 0 {
+		log.Printf("Reminder scheduler disabled: no offsets or channels configured")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, time.Now()); err != nil {
+			log.Printf("Reminder scan failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick 执行一次扫描：为到期的面试和 offer 生成提醒记录，然后投递所有待发送的提醒
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	if err := s.schedule(now); err != nil {
+		return err
+	}
+	if err := s.scheduleOffers(now); err != nil {
+		return err
+	}
+
+	due, err := s.reminders.FindDue(now, batchSize)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.deliver(ctx, reminder, now)
+	}
+
+	dueOffers, err := s.reminders.FindDueOffers(now, batchSize)
+	if err != nil {
+		return err
+	}
+	for _, reminder := range dueOffers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.deliverOffer(ctx, reminder, now)
+	}
+	return nil
+}
+
+// schedule 为已进入提醒窗口的面试创建提醒记录。多个提前量同时到期时（例如面试是临时安排的）
+// 只保留最近的一个，避免一次收到多条提醒
+func (s *Scheduler) schedule(now time.Time) error {
+	if len(s.offsets) == 0 {
+		return nil
+	}
+	maxOffset := s.offsets[len(s.offsets)-1]
+	interviews, err := s.interviews.FindScheduledBetween(now, now.Add(maxOffset))
+	if err != nil {
+		return err
+	}
+
+	for _, interview := range interviews {
+		for _, offset := range s.offsets {
+			fireAt := interview.StartTime.Add(-offset)
+			if fireAt.After(now) {
+				continue
+			}
+			for name := range s.channels {
+				err := s.reminders.Ensure(&model.InterviewReminder{
+					InterviewID:   interview.ID,
+					UserID:        interview.UserID,
+					Channel:       name,
+					OffsetMinutes: int(offset / time.Minute),
+					StartTime:     interview.StartTime,
+					FireAt:        fireAt,
+					Status:        model.ReminderStatusPending,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// scheduleOffers 为已进入提醒窗口的待答复 offer 创建截止提醒记录，规则同 schedule
+func (s *Scheduler) scheduleOffers(now time.Time) error {
+	if len(s.offerOffsets) == 0 {
+		return nil
+	}
+	maxOffset := s.offerOffsets[len(s.offerOffsets)-1]
+	offers, err := s.offers.FindPendingExpiringBetween(now, now.Add(maxOffset))
+	if err != nil {
+		return err
+	}
+
+	for _, offer := range offers {
+		for _, offset := range s.offerOffsets {
+			fireAt := offer.ExpiresAt.Add(-offset)
+			if fireAt.After(now) {
+				continue
+			}
+			for name := range s.channels {
+				err := s.reminders.EnsureOffer(&model.OfferReminder{
+					OfferID:       offer.ID,
+					UserID:        offer.UserID,
+					Channel:       name,
+					OffsetMinutes: int(offset / time.Minute),
+					ExpiresAt:     *offer.ExpiresAt,
+					FireAt:        fireAt,
+					Status:        model.ReminderStatusPending,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) deliver(ctx context.Context, reminder model.InterviewReminder, now time.Time) {
+	ok, err := s.reminders.Claim(reminder.ID, now, claimLease)
+	if err != nil || !ok {
+		return
+	}
+
+	channel, ok := s.channels[reminder.Channel]
+	if !ok {
+		s.skip(reminder, "channel not configured")
+		return
+	}
+
+	interview, err := s.interviews.FindByID(reminder.UserID, reminder.InterviewID)
+	if err != nil {
+		s.skip(reminder, "interview not found")
+		return
+	}
+	// 面试已改期或取消时，这条提醒作废
+	if interview.Status != model.InterviewStatusScheduled || !interview.StartTime.Equal(reminder.StartTime) {
+		s.skip(reminder, "interview rescheduled or no longer scheduled")
+		return
+	}
+	if !interview.StartTime.After(now) {
+		s.skip(reminder, "interview already started")
+		return
+	}
+
+	user, err := s.users.FindByID(reminder.UserID)
+	if err != nil {
+		s.skip(reminder, "user not found")
+		return
+	}
+
+	err = channel.Deliver(ctx, Notification{
+		Interview: *interview,
+		User:      *user,
+		Offset:    time.Duration(reminder.OffsetMinutes) * time.Minute,
+	})
+	switch {
+	case err == nil:
+		if err := s.reminders.MarkSent(reminder.ID, time.Now()); err != nil {
+			log.Printf("Reminder %d sent but failed to mark: %v", reminder.ID, err)
+		}
+	case errors.Is(err, ErrNoRecipient):
+		s.skip(reminder, err.Error())
+	default:
+		final := reminder.Attempts+1 >= maxAttempts
+		log.Printf("Reminder %d via %s failed (attempt %d): %v", reminder.ID, reminder.Channel, reminder.Attempts+1, err)
+		if err := s.reminders.MarkFailed(reminder.ID, err.Error(), final); err != nil {
+			log.Printf("Failed to record reminder %d failure: %v", reminder.ID, err)
+		}
+	}
+}
+
+func (s *Scheduler) skip(reminder model.InterviewReminder, reason string) {
+	if err := s.reminders.MarkSkipped(reminder.ID, reason); err != nil {
+		log.Printf("Failed to skip reminder %d: %v", reminder.ID, err)
+	}
+}
+
+func (s *Scheduler) deliverOffer(ctx context.Context, reminder model.OfferReminder, now time.Time) {
+	ok, err := s.reminders.ClaimOffer(reminder.ID, now, claimLease)
+	if err != nil || !ok {
+		return
+	}
+
+	channel, ok := s.channels[reminder.Channel]
+	if !ok {
+		s.skipOffer(reminder, "channel not configured")
+		return
+	}
+
+	offer, err := s.offers.FindByID(reminder.UserID, reminder.OfferID)
+	if err != nil {
+		s.skipOffer(reminder, "offer not found")
+		return
+	}
+	// 已答复或截止时间已修改时，这条提醒作废
+	if offer.Status != model.OfferStatusPending || offer.ExpiresAt == nil || !offer.ExpiresAt.Equal(reminder.ExpiresAt) {
+		s.skipOffer(reminder, "offer answered or deadline changed")
+		return
+	}
+	if !offer.ExpiresAt.After(now) {
+		s.skipOffer(reminder, "offer already expired")
+		return
+	}
+
+	user, err := s.users.FindByID(reminder.UserID)
+	if err != nil {
+		s.skipOffer(reminder, "user not found")
+		return
+	}
+
+	err = channel.Deliver(ctx, Notification{
+		Offer:  offer,
+		User:   *user,
+		Offset: time.Duration(reminder.OffsetMinutes) * time.Minute,
+	})
+	switch {
+	case err == nil:
+		if err := s.reminders.MarkOfferSent(reminder.ID, time.Now()); err != nil {
+			log.Printf("Offer reminder %d sent but failed to mark: %v", reminder.ID, err)
+		}
+	case errors.Is(err, ErrNoRecipient):
+		s.skipOffer(reminder, err.Error())
+	default:
+		final := reminder.Attempts+1 >= maxAttempts
+		log.Printf("Offer reminder %d via %s failed (attempt %d): %v", reminder.ID, reminder.Channel, reminder.Attempts+1, err)
+		if err := s.reminders.MarkOfferFailed(reminder.ID, err.Error(), final); err != nil {
+			log.Printf("Failed to record offer reminder %d failure: %v", reminder.ID, err)
+		}
+	}
+}
+
+func (s *Scheduler) skipOffer(reminder model.OfferReminder, reason string) {
+	if err := s.reminders.MarkOfferSkipped(reminder.ID, reason); err != nil {
+		log.Printf("Failed to skip offer reminder %d: %v", reminder.ID, err)
+	}
+}
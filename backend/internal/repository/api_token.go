@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type APITokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository() *APITokenRepository {
+	return &APITokenRepository{db: database.GetDB()}
+}
+
+func (r *APITokenRepository) Create(token *model.APIToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByUser 返回未吊销的令牌（含已过期的），最新创建的在前
+func (r *APITokenRepository) FindByUser(userID int64) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// FindActiveByHash 查找未吊销且未过期的令牌
+func (r *APITokenRepository) FindActiveByHash(hash string, now time.Time) (*model.APIToken, error) {
+	var token model.APIToken
+	err := r.db.Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?", hash, now).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Revoke 吊销用户的令牌，不存在或已吊销时返回 gorm.ErrRecordNotFound
+func (r *APITokenRepository) Revoke(userID, id int64, now time.Time) error {
+	result := r.db.Model(&model.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Touch 记录最近使用时间和 IP，距上次记录不足 interval 且 IP 未变时跳过
+func (r *APITokenRepository) Touch(id int64, ip string, now time.Time, interval time.Duration) error {
+	return r.db.Model(&model.APIToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ? OR last_used_ip <> ?)", id, now.Add(-interval), ip).
+		Updates(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}).Error
+}
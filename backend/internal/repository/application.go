@@ -2,6 +2,7 @@ package repository
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"offermatrix/internal/model"
 	"offermatrix/pkg/database"
 )
@@ -14,58 +15,181 @@ func NewApplicationRepository() *ApplicationRepository {
 	return &ApplicationRepository{db: database.GetDB()}
 }
 
-func (r *ApplicationRepository) Create(app *model.Application) error {
-	return r.db.Create(app).Error
+// Create 创建申请，并记录初始状态事件
+func (r *ApplicationRepository) Create(app *model.Application, reason string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createApplication(tx, app, reason)
+	})
 }
 
-func (r *ApplicationRepository) FindAll() ([]model.Application, error) {
+// createApplication 写入申请及其初始状态记录
+func createApplication(tx *gorm.DB, app *model.Application, reason string) error {
+	if err := tx.Create(app).Error; err != nil {
+		return err
+	}
+	return tx.Create(&model.ApplicationStatusEvent{
+		ApplicationID: app.ID,
+		UserID:        app.UserID,
+		ToStatus:      app.CurrentStatus,
+		Reason:        reason,
+	}).Error
+}
+
+func (r *ApplicationRepository) FindAll(userID int64) ([]model.Application, error) {
 	var apps []model.Application
-	err := r.db.Order("updated_at DESC").Find(&apps).Error
+	err := r.db.Preload("Compensation").Where("user_id = ?", userID).Order("updated_at DESC").Find(&apps).Error
 	return apps, err
 }
 
-func (r *ApplicationRepository) FindByID(id int64) (*model.Application, error) {
+func (r *ApplicationRepository) FindByID(userID, id int64) (*model.Application, error) {
 	var app model.Application
 	err := r.db.Preload("Interviews", func(db *gorm.DB) *gorm.DB {
 		return db.Order("start_time ASC")
-	}).First(&app, id).Error
+	}).Preload("Compensation").Preload("Offer").Where("user_id = ?", userID).First(&app, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &app, nil
 }
 
-func (r *ApplicationRepository) Update(app *model.Application) error {
-	return r.db.Model(app).Updates(map[string]interface{}{
-		"company_name":    app.CompanyName,
-		"job_title":       app.JobTitle,
-		"current_status":  app.CurrentStatus,
-		"salary":          app.Salary,
-		"job_description": app.JobDescription,
-		"jd_analysis":     app.JDAnalysis,
-	}).Error
+// FindByIDs 按 ids 的顺序返回属于该用户的申请及其结构化薪资和 offer，不存在的 id 跳过
+func (r *ApplicationRepository) FindByIDs(userID int64, ids []int64) ([]model.Application, error) {
+	var found []model.Application
+	err := r.db.Preload("Compensation").Preload("Offer").Where("user_id = ? AND id IN ?", userID, ids).Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]model.Application, len(found))
+	for _, app := range found {
+		byID[app.ID] = app
+	}
+	apps := make([]model.Application, 0, len(found))
+	for _, id := range ids {
+		if app, ok := byID[id]; ok {
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
 }
 
-func (r *ApplicationRepository) Delete(id int64) error {
-	// 先删除关联的面试
-	if err := r.db.Where("application_id = ?", id).Delete(&model.Interview{}).Error; err != nil {
-		return err
+// Exists 判断申请是否存在且属于该用户
+func (r *ApplicationRepository) Exists(userID, id int64) bool {
+	var count int64
+	r.db.Model(&model.Application{}).Where("id = ? AND user_id = ?", id, userID).Count(&count)
+	return count > 0
+}
+
+// Update 更新申请；event 不为空时在同一事务中记录状态流转
+func (r *ApplicationRepository) Update(app *model.Application, event *model.ApplicationStatusEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 申请上预加载的面试、薪资和 offer 由各自的接口维护，这里不回写
+		err := tx.Model(app).Omit(clause.Associations).Where("user_id = ?", app.UserID).Updates(map[string]interface{}{
+			"company_name":    app.CompanyName,
+			"job_title":       app.JobTitle,
+			"current_status":  app.CurrentStatus,
+			"salary":          app.Salary,
+			"city":            app.City,
+			"job_description": app.JobDescription,
+			"jd_analysis":     app.JDAnalysis,
+		}).Error
+		if err != nil || event == nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
+
+// UpdateJDAnalysis 只写回 JD 分析结果，jobDescription 非空时同时更新 JD。
+// 分析耗时较长，不能用整行回写覆盖期间对申请的其他修改
+func (r *ApplicationRepository) UpdateJDAnalysis(userID, id int64, jobDescription, analysis string) error {
+	columns := map[string]interface{}{"jd_analysis": analysis}
+	if jobDescription != "" {
+		columns["job_description"] = jobDescription
+	}
+	result := r.db.Model(&model.Application{}).Where("id = ? AND user_id = ?", id, userID).UpdateColumns(columns)
+	if result.Error != nil {
+		return result.Error
 	}
-	return r.db.Delete(&model.Application{}, id).Error
+	if result.RowsAffected == 0 && !r.Exists(userID, id) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindStatusEvents 按时间顺序返回申请的状态流转记录
+func (r *ApplicationRepository) FindStatusEvents(userID, appID int64) ([]model.ApplicationStatusEvent, error) {
+	var events []model.ApplicationStatusEvent
+	err := r.db.Where("application_id = ? AND user_id = ?", appID, userID).
+		Order("created_at ASC, id ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// Delete 删除申请及其面试、状态记录、薪资、offer 和联系人关联。子表先于申请删除，
+// 兼容 AutoMigrate 创建的外键和 schema.sql 中的级联删除
+func (r *ApplicationRepository) Delete(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var app model.Application
+		if err := tx.Select("id").Where("user_id = ?", userID).First(&app, id).Error; err != nil {
+			return err
+		}
+
+		var interviewIDs []int64
+		if err := tx.Model(&model.Interview{}).Where("application_id = ? AND user_id = ?", id, userID).Pluck("id", &interviewIDs).Error; err != nil {
+			return err
+		}
+		if len(interviewIDs) > 0 {
+			if err := deleteCommentsByInterviews(tx, interviewIDs); err != nil {
+				return err
+			}
+			if err := tx.Where("interview_id IN ?", interviewIDs).Delete(&model.ContactInterview{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("interview_id IN ?", interviewIDs).Delete(&model.InterviewReminder{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&model.Interview{}, interviewIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		var offerIDs []int64
+		if err := tx.Model(&model.Offer{}).Where("application_id = ? AND user_id = ?", id, userID).Pluck("id", &offerIDs).Error; err != nil {
+			return err
+		}
+		if err := deleteOffers(tx, offerIDs); err != nil {
+			return err
+		}
+
+		children := []interface{}{
+			&model.ApplicationStatusEvent{},
+			&model.ContactApplication{},
+			&model.Compensation{},
+			&model.OfferScore{},
+		}
+		for _, m := range children {
+			if err := tx.Where("application_id = ? AND user_id = ?", id, userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&model.Application{}, id).Error
+	})
 }
 
-func (r *ApplicationRepository) Search(keyword string) ([]model.Application, error) {
+func (r *ApplicationRepository) Search(userID int64, keyword string) ([]model.Application, error) {
 	var apps []model.Application
-	err := r.db.Where("company_name LIKE ? OR job_title LIKE ?",
-		"%"+keyword+"%", "%"+keyword+"%").
+	err := r.db.Preload("Compensation").Where("user_id = ?", userID).
+		Where("company_name LIKE ? OR job_title LIKE ?", "%"+keyword+"%", "%"+keyword+"%").
 		Order("updated_at DESC").
 		Find(&apps).Error
 	return apps, err
 }
 
-func (r *ApplicationRepository) SearchWithFilters(keyword string, statuses []string) ([]model.Application, error) {
+func (r *ApplicationRepository) SearchWithFilters(userID int64, keyword string, statuses []string) ([]model.Application, error) {
 	var apps []model.Application
-	query := r.db.Model(&model.Application{})
+	query := r.db.Model(&model.Application{}).Preload("Compensation").Where("user_id = ?", userID)
 
 	if keyword != "" {
 		query = query.Where("company_name LIKE ? OR job_title LIKE ?",
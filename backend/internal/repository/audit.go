@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{db: database.GetDB()}
+}
+
+func (r *AuditRepository) Create(entry *model.AuditLog) error {
+	entry.Subject = truncate(entry.Subject, 255)
+	entry.Detail = truncate(entry.Detail, 500)
+	return r.db.Create(entry).Error
+}
+
+// List 按时间倒序分页查询，userID 为 0、action 为空时不过滤
+func (r *AuditRepository) List(userID int64, action string, offset, limit int) ([]model.AuditLog, int64, error) {
+	query := r.db.Model(&model.AuditLog{})
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []model.AuditLog
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, total, err
+}
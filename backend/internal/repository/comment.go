@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{db: database.GetDB()}
+}
+
+// Create 写入评论及其提及
+func (r *CommentRepository) Create(comment *model.ReviewComment, mentionIDs []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return createMentions(tx, comment.ID, mentionIDs)
+	})
+}
+
+func (r *CommentRepository) FindByID(interviewID, id int64) (*model.ReviewComment, error) {
+	var comment model.ReviewComment
+	err := r.db.Where("interview_id = ?", interviewID).First(&comment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// commentRow 评论连同作者用户名
+type commentRow struct {
+	model.ReviewComment
+	AuthorUsername string
+}
+
+// FindByInterview 按时间顺序返回面试下的全部评论，已删除的评论清空正文后保留占位
+func (r *CommentRepository) FindByInterview(interviewID int64) ([]model.ReviewCommentResponse, error) {
+	var rows []commentRow
+	err := r.db.Table("review_comments AS c").
+		Select("c.*, u.username AS author_username").
+		Joins("LEFT JOIN users AS u ON u.id = c.author_id").
+		Where("c.interview_id = ?", interviewID).
+		Order("c.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var mentions []struct {
+		CommentID int64
+		Username  string
+	}
+	err = r.db.Table("review_comment_mentions AS m").
+		Select("m.comment_id, u.username").
+		Joins("JOIN review_comments AS c ON c.id = m.comment_id").
+		Joins("JOIN users AS u ON u.id = m.user_id").
+		Where("c.interview_id = ?", interviewID).
+		Order("m.id ASC").
+		Scan(&mentions).Error
+	if err != nil {
+		return nil, err
+	}
+	byComment := make(map[int64][]string)
+	for _, m := range mentions {
+		byComment[m.CommentID] = append(byComment[m.CommentID], m.Username)
+	}
+
+	comments := make([]model.ReviewCommentResponse, 0, len(rows))
+	for _, row := range rows {
+		names := byComment[row.ID]
+		if names == nil {
+			names = []string{}
+		}
+		comments = append(comments, model.ReviewCommentResponse{
+			ReviewComment:  row.ReviewComment,
+			AuthorUsername: row.AuthorUsername,
+			Mentions:       names,
+		})
+	}
+	return comments, nil
+}
+
+// UpdateBody 修改正文并替换提及
+func (r *CommentRepository) UpdateBody(id int64, body string, mentionIDs []int64, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ReviewComment{}).Where("id = ? AND deleted_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"body":      body,
+				"edited_at": now,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", id).Delete(&model.ReviewCommentMention{}).Error; err != nil {
+			return err
+		}
+		return createMentions(tx, id, mentionIDs)
+	})
+}
+
+// SoftDelete 清空正文并标记删除，回复仍挂在原位置
+func (r *CommentRepository) SoftDelete(id int64, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ReviewComment{}).Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"body":       "",
+				"line_start": nil,
+				"line_end":   nil,
+				"deleted_at": now,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Where("comment_id = ?", id).Delete(&model.ReviewCommentMention{}).Error
+	})
+}
+
+// MarkRead 把用户在该面试下的已读位置推进到当前最新的评论
+func (r *CommentRepository) MarkRead(userID, interviewID int64) error {
+	var latest int64
+	if err := r.db.Model(&model.ReviewComment{}).Select("COALESCE(MAX(id), 0)").
+		Where("interview_id = ?", interviewID).Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_read_comment_id": gorm.Expr("GREATEST(last_read_comment_id, ?)", latest),
+			"updated_at":           time.Now(),
+		}),
+	}).Create(&model.ReviewCommentRead{
+		UserID:            userID,
+		InterviewID:       interviewID,
+		LastReadCommentID: latest,
+	}).Error
+}
+
+// CountUnread 统计 ownerIDs 名下各场面试中他人发表、userID 尚未读过的评论数
+func (r *CommentRepository) CountUnread(userID int64, ownerIDs []int64) ([]model.UnreadCommentCount, error) {
+	var counts []model.UnreadCommentCount
+	if len(ownerIDs) == 0 {
+		return counts, nil
+	}
+
+	err := r.db.Table("review_comments AS c").
+		Select("c.interview_id, c.owner_id, COUNT(DISTINCT c.id) AS unread, COUNT(m.id) AS mentions").
+		Joins("LEFT JOIN review_comment_reads AS r ON r.interview_id = c.interview_id AND r.user_id = ?", userID).
+		Joins("LEFT JOIN review_comment_mentions AS m ON m.comment_id = c.id AND m.user_id = ?", userID).
+		Where("c.owner_id IN ? AND c.author_id <> ? AND c.deleted_at IS NULL", ownerIDs, userID).
+		Where("c.id > COALESCE(r.last_read_comment_id, 0)").
+		Group("c.interview_id, c.owner_id").
+		Order("c.interview_id ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// deleteCommentsByInterviews 删除面试时清理评论、提及和已读记录，interviewIDs 可以是 ID 列表或子查询
+func deleteCommentsByInterviews(tx *gorm.DB, interviewIDs interface{}) error {
+	if err := tx.Where("comment_id IN (?)",
+		tx.Model(&model.ReviewComment{}).Select("id").Where("interview_id IN (?)", interviewIDs)).
+		Delete(&model.ReviewCommentMention{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("interview_id IN (?)", interviewIDs).Delete(&model.ReviewCommentRead{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("interview_id IN (?)", interviewIDs).Delete(&model.ReviewComment{}).Error
+}
+
+func createMentions(tx *gorm.DB, commentID int64, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	mentions := make([]model.ReviewCommentMention, 0, len(userIDs))
+	for _, id := range userIDs {
+		mentions = append(mentions, model.ReviewCommentMention{CommentID: commentID, UserID: id})
+	}
+	return tx.Create(&mentions).Error
+}
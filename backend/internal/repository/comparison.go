@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type ComparisonRepository struct {
+	db *gorm.DB
+}
+
+func NewComparisonRepository() *ComparisonRepository {
+	return &ComparisonRepository{db: database.GetDB()}
+}
+
+func (r *ComparisonRepository) FindCriteria(userID int64) ([]model.OfferCriterion, error) {
+	criteria := []model.OfferCriterion{}
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&criteria).Error
+	return criteria, err
+}
+
+// CriterionNameExists 判断用户是否已有同名评价维度，excludeID 用于修改时排除自身
+func (r *ComparisonRepository) CriterionNameExists(userID int64, name string, excludeID int64) bool {
+	var count int64
+	r.db.Model(&model.OfferCriterion{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, excludeID).
+		Count(&count)
+	return count > 0
+}
+
+func (r *ComparisonRepository) CreateCriterion(criterion *model.OfferCriterion) error {
+	return r.db.Create(criterion).Error
+}
+
+func (r *ComparisonRepository) UpdateCriterion(criterion *model.OfferCriterion) error {
+	result := r.db.Model(criterion).Where("user_id = ?", criterion.UserID).Updates(map[string]interface{}{
+		"name":   criterion.Name,
+		"weight": criterion.Weight,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 && !r.criterionExists(criterion.UserID, criterion.ID) {
+		return gorm.ErrRecordNotFound
+	}
+	return r.db.First(criterion, criterion.ID).Error
+}
+
+// DeleteCriterion 删除评价维度及其下的打分
+func (r *ComparisonRepository) DeleteCriterion(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&model.OfferCriterion{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("criterion_id = ? AND user_id = ?", id, userID).Delete(&model.OfferScore{}).Error
+	})
+}
+
+func (r *ComparisonRepository) criterionExists(userID, id int64) bool {
+	var count int64
+	r.db.Model(&model.OfferCriterion{}).Where("id = ? AND user_id = ?", id, userID).Count(&count)
+	return count > 0
+}
+
+// CountCriteria 返回 ids 中属于该用户的评价维度数量
+func (r *ComparisonRepository) CountCriteria(userID int64, ids []int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.OfferCriterion{}).Where("user_id = ? AND id IN ?", userID, ids).Count(&count).Error
+	return count, err
+}
+
+// FindScores 返回这些申请的全部打分
+func (r *ComparisonRepository) FindScores(userID int64, appIDs []int64) ([]model.OfferScore, error) {
+	scores := []model.OfferScore{}
+	err := r.db.Where("user_id = ? AND application_id IN ?", userID, appIDs).
+		Order("application_id ASC, criterion_id ASC").
+		Find(&scores).Error
+	return scores, err
+}
+
+// ReplaceScores 在一个事务中替换申请的全部打分
+func (r *ComparisonRepository) ReplaceScores(userID, appID int64, scores []model.OfferScore) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("application_id = ? AND user_id = ?", appID, userID).Delete(&model.OfferScore{}).Error; err != nil {
+			return err
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+		return tx.Create(&scores).Error
+	})
+}
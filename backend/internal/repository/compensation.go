@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type CompensationRepository struct {
+	db *gorm.DB
+}
+
+func NewCompensationRepository() *CompensationRepository {
+	return &CompensationRepository{db: database.GetDB()}
+}
+
+// Save 写入申请的结构化薪资，已有记录时整体替换
+func (r *CompensationRepository) Save(comp *model.Compensation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.Compensation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("application_id = ? AND user_id = ?", comp.ApplicationID, comp.UserID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(comp).Error
+		}
+		if err != nil {
+			return err
+		}
+		comp.ID = existing.ID
+		comp.CreatedAt = existing.CreatedAt
+		return tx.Save(comp).Error
+	})
+}
+
+func (r *CompensationRepository) DeleteByApplication(userID, appID int64) error {
+	return r.db.Where("application_id = ? AND user_id = ?", appID, userID).Delete(&model.Compensation{}).Error
+}
+
+// Backfill 为有薪资文本但还没有结构化薪资的申请解析并补上记录，返回补上的条数；解析不了的跳过
+func (r *CompensationRepository) Backfill() (int, error) {
+	var apps []model.Application
+	err := r.db.Select("id", "user_id", "salary").
+		Where("salary <> ''").
+		Where("NOT EXISTS (?)", r.db.Model(&model.Compensation{}).Select("1").Where("compensations.application_id = applications.id")).
+		Find(&apps).Error
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, app := range apps {
+		comp, err := model.ParseCompensation(app.Salary)
+		if err != nil {
+			continue
+		}
+		comp.ApplicationID = app.ID
+		comp.UserID = app.UserID
+		if err := r.db.Create(comp).Error; err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
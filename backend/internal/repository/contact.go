@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type ContactRepository struct {
+	db *gorm.DB
+}
+
+func NewContactRepository() *ContactRepository {
+	return &ContactRepository{db: database.GetDB()}
+}
+
+// FindAll 返回用户的联系人，keyword 非空时按姓名、公司、邮箱模糊匹配
+func (r *ContactRepository) FindAll(userID int64, keyword string) ([]model.Contact, error) {
+	contacts := []model.Contact{}
+	query := r.db.Where("user_id = ?", userID)
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("name LIKE ? OR company LIKE ? OR email LIKE ?", like, like, like)
+	}
+	err := query.Order("name ASC, id ASC").Find(&contacts).Error
+	return contacts, err
+}
+
+func (r *ContactRepository) FindByID(userID, id int64) (*model.Contact, error) {
+	var contact model.Contact
+	err := r.db.Where("user_id = ?", userID).First(&contact, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// FindDetail 返回联系人及其关联的申请、面试和联系记录
+func (r *ContactRepository) FindDetail(userID, id int64) (*model.ContactDetail, error) {
+	contact, err := r.FindByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	detail := &model.ContactDetail{
+		Contact:      *contact,
+		Applications: []model.ContactApplicationRef{},
+		Interviews:   []model.ContactInterviewRef{},
+	}
+
+	err = r.db.Table("contact_applications ca").
+		Select("a.id, a.company_name, a.job_title, a.current_status").
+		Joins("JOIN applications a ON a.id = ca.application_id").
+		Where("ca.contact_id = ? AND ca.user_id = ?", id, userID).
+		Order("a.updated_at DESC").
+		Scan(&detail.Applications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Table("contact_interviews ci").
+		Select("i.id, i.application_id, a.company_name, i.round_name, i.start_time, i.status").
+		Joins("JOIN interviews i ON i.id = ci.interview_id").
+		Joins("LEFT JOIN applications a ON a.id = i.application_id").
+		Where("ci.contact_id = ? AND ci.user_id = ?", id, userID).
+		Order("i.start_time DESC").
+		Scan(&detail.Interviews).Error
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Interactions, err = r.FindInteractions(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// FindByApplication 返回与申请关联的联系人
+func (r *ContactRepository) FindByApplication(userID, appID int64) ([]model.Contact, error) {
+	contacts := []model.Contact{}
+	err := r.db.Where("user_id = ? AND id IN (?)", userID,
+		r.db.Model(&model.ContactApplication{}).Select("contact_id").Where("application_id = ? AND user_id = ?", appID, userID)).
+		Order("name ASC, id ASC").
+		Find(&contacts).Error
+	return contacts, err
+}
+
+// FindByInterview 返回与面试关联的联系人
+func (r *ContactRepository) FindByInterview(userID, interviewID int64) ([]model.Contact, error) {
+	contacts := []model.Contact{}
+	err := r.db.Where("user_id = ? AND id IN (?)", userID,
+		r.db.Model(&model.ContactInterview{}).Select("contact_id").Where("interview_id = ? AND user_id = ?", interviewID, userID)).
+		Order("name ASC, id ASC").
+		Find(&contacts).Error
+	return contacts, err
+}
+
+// FindNeedingFollowUp 返回最近一次联系（没有联系记录时按创建时间）早于 before 的联系人，最久未联系的排在前面
+func (r *ContactRepository) FindNeedingFollowUp(userID int64, before time.Time) ([]model.Contact, error) {
+	contacts := []model.Contact{}
+	err := r.db.Where("user_id = ? AND COALESCE(last_contacted_at, created_at) < ?", userID, before).
+		Order("COALESCE(last_contacted_at, created_at) ASC, id ASC").
+		Find(&contacts).Error
+	return contacts, err
+}
+
+func (r *ContactRepository) Create(contact *model.Contact) error {
+	return r.db.Create(contact).Error
+}
+
+func (r *ContactRepository) Update(contact *model.Contact) error {
+	return r.db.Model(contact).Where("user_id = ?", contact.UserID).Updates(map[string]interface{}{
+		"name":     contact.Name,
+		"role":     contact.Role,
+		"company":  contact.Company,
+		"email":    contact.Email,
+		"phone":    contact.Phone,
+		"wechat":   contact.WeChat,
+		"linkedin": contact.LinkedIn,
+		"notes":    contact.Notes,
+	}).Error
+}
+
+// Delete 删除联系人及其关联和联系记录
+func (r *ContactRepository) Delete(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&model.Contact{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		for _, m := range []interface{}{&model.ContactApplication{}, &model.ContactInterview{}, &model.ContactInteraction{}} {
+			if err := tx.Where("contact_id = ? AND user_id = ?", id, userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LinkApplication 关联联系人与申请，申请不属于该用户时返回 gorm.ErrRecordNotFound，重复关联会被忽略
+func (r *ContactRepository) LinkApplication(userID, contactID, appID int64) error {
+	if !r.owns(&model.Application{}, userID, appID) {
+		return gorm.ErrRecordNotFound
+	}
+	link := model.ContactApplication{ContactID: contactID, ApplicationID: appID, UserID: userID}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+}
+
+func (r *ContactRepository) UnlinkApplication(userID, contactID, appID int64) error {
+	result := r.db.Where("contact_id = ? AND application_id = ? AND user_id = ?", contactID, appID, userID).
+		Delete(&model.ContactApplication{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// LinkInterview 关联联系人与面试，面试不属于该用户时返回 gorm.ErrRecordNotFound，重复关联会被忽略
+func (r *ContactRepository) LinkInterview(userID, contactID, interviewID int64) error {
+	if !r.owns(&model.Interview{}, userID, interviewID) {
+		return gorm.ErrRecordNotFound
+	}
+	link := model.ContactInterview{ContactID: contactID, InterviewID: interviewID, UserID: userID}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error
+}
+
+func (r *ContactRepository) UnlinkInterview(userID, contactID, interviewID int64) error {
+	result := r.db.Where("contact_id = ? AND interview_id = ? AND user_id = ?", contactID, interviewID, userID).
+		Delete(&model.ContactInterview{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindInteractions 返回联系人的联系记录，按时间倒序
+func (r *ContactRepository) FindInteractions(userID, contactID int64) ([]model.ContactInteraction, error) {
+	interactions := []model.ContactInteraction{}
+	err := r.db.Where("contact_id = ? AND user_id = ?", contactID, userID).
+		Order("occurred_at DESC, id DESC").
+		Find(&interactions).Error
+	return interactions, err
+}
+
+// CreateInteraction 记录一次联系，并刷新联系人的最近联系时间
+func (r *ContactRepository) CreateInteraction(interaction *model.ContactInteraction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(interaction).Error; err != nil {
+			return err
+		}
+		return refreshLastContacted(tx, interaction.ContactID)
+	})
+}
+
+// DeleteInteraction 删除一条联系记录，并刷新联系人的最近联系时间
+func (r *ContactRepository) DeleteInteraction(userID, contactID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("contact_id = ? AND user_id = ?", contactID, userID).Delete(&model.ContactInteraction{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return refreshLastContacted(tx, contactID)
+	})
+}
+
+func (r *ContactRepository) owns(m interface{}, userID, id int64) bool {
+	var count int64
+	r.db.Model(m).Where("id = ? AND user_id = ?", id, userID).Count(&count)
+	return count > 0
+}
+
+// refreshLastContacted 按联系记录重新计算最近联系时间，补录较早的记录或删除记录时都能保持正确
+func refreshLastContacted(tx *gorm.DB, contactID int64) error {
+	return tx.Model(&model.Contact{}).Where("id = ?", contactID).
+		UpdateColumn("last_contacted_at", tx.Model(&model.ContactInteraction{}).Select("MAX(occurred_at)").Where("contact_id = ?", contactID)).
+		Error
+}
@@ -20,40 +20,111 @@ func (r *InterviewRepository) Create(interview *model.Interview) error {
 	return r.db.Create(interview).Error
 }
 
-func (r *InterviewRepository) FindAll() ([]model.Interview, error) {
+// CreateImported 在一个事务中写入从日历邀请导入的面试。companies[i] 非空表示第 i 场面试需要新建申请，
+// 同一公司只新建一个；返回新建的申请
+func (r *InterviewRepository) CreateImported(interviews []*model.Interview, companies []string) ([]*model.Application, error) {
+	var apps []*model.Application
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		created := make(map[string]int64)
+		for i, interview := range interviews {
+			if company := companies[i]; company != "" {
+				appID, ok := created[company]
+				if !ok {
+					app := &model.Application{
+						UserID:        interview.UserID,
+						CompanyName:   company,
+						CurrentStatus: model.StatusInProcess,
+					}
+					if err := createApplication(tx, app, "imported from calendar invitation"); err != nil {
+						return err
+					}
+					appID = app.ID
+					created[company] = appID
+					apps = append(apps, app)
+				}
+				interview.ApplicationID = appID
+			}
+			if err := tx.Create(interview).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+func (r *InterviewRepository) FindAll(userID int64) ([]model.Interview, error) {
 	var interviews []model.Interview
-	err := r.db.Preload("Application").Order("start_time ASC").Find(&interviews).Error
+	err := r.db.Preload("Application").
+		Where("user_id = ?", userID).
+		Order("start_time ASC").
+		Find(&interviews).Error
 	return interviews, err
 }
 
-func (r *InterviewRepository) FindByID(id int64) (*model.Interview, error) {
+func (r *InterviewRepository) FindByID(userID, id int64) (*model.Interview, error) {
 	var interview model.Interview
-	err := r.db.Preload("Application").First(&interview, id).Error
+	err := r.db.Preload("Application").Where("user_id = ?", userID).First(&interview, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &interview, nil
 }
 
-func (r *InterviewRepository) FindByTimeRange(start, end time.Time) ([]model.Interview, error) {
+func (r *InterviewRepository) FindByTimeRange(userID int64, start, end time.Time) ([]model.Interview, error) {
 	var interviews []model.Interview
 	err := r.db.Preload("Application").
+		Where("user_id = ?", userID).
 		Where("start_time >= ? AND start_time <= ?", start, end).
 		Order("start_time ASC").
 		Find(&interviews).Error
 	return interviews, err
 }
 
-func (r *InterviewRepository) FindByApplicationID(appID int64) ([]model.Interview, error) {
+// FindOverlapping 查找与 [start, end) 时间段重叠的待进行面试，excludeID 用于更新时排除自身
+func (r *InterviewRepository) FindOverlapping(userID int64, start, end time.Time, excludeID int64) ([]model.Interview, error) {
+	var interviews []model.Interview
+	err := r.db.Preload("Application").
+		Where("user_id = ? AND status = ? AND id <> ?", userID, model.InterviewStatusScheduled, excludeID).
+		Where("start_time < ? AND end_time > ?", end, start).
+		Order("start_time ASC").
+		Find(&interviews).Error
+	return interviews, err
+}
+
+// FindScheduledBetween 返回所有用户在时间段内开始的待进行面试，供后台任务使用
+func (r *InterviewRepository) FindScheduledBetween(start, end time.Time) ([]model.Interview, error) {
+	var interviews []model.Interview
+	err := r.db.Preload("Application").
+		Where("status = ? AND start_time > ? AND start_time <= ?", model.InterviewStatusScheduled, start, end).
+		Order("start_time ASC").
+		Find(&interviews).Error
+	return interviews, err
+}
+
+// FindScheduled 返回用户所有待进行的面试
+func (r *InterviewRepository) FindScheduled(userID int64) ([]model.Interview, error) {
 	var interviews []model.Interview
-	err := r.db.Where("application_id = ?", appID).
+	err := r.db.Preload("Application").
+		Where("user_id = ? AND status = ?", userID, model.InterviewStatusScheduled).
+		Order("start_time ASC").
+		Find(&interviews).Error
+	return interviews, err
+}
+
+func (r *InterviewRepository) FindByApplicationID(userID, appID int64) ([]model.Interview, error) {
+	var interviews []model.Interview
+	err := r.db.Where("application_id = ? AND user_id = ?", appID, userID).
 		Order("start_time ASC").
 		Find(&interviews).Error
 	return interviews, err
 }
 
 func (r *InterviewRepository) Update(interview *model.Interview) error {
-	return r.db.Model(interview).Updates(map[string]interface{}{
+	return r.db.Model(interview).Where("user_id = ?", interview.UserID).Updates(map[string]interface{}{
 		"application_id": interview.ApplicationID,
 		"round_name":     interview.RoundName,
 		"start_time":     interview.StartTime,
@@ -64,11 +135,41 @@ func (r *InterviewRepository) Update(interview *model.Interview) error {
 	}).Error
 }
 
-func (r *InterviewRepository) UpdateReview(id int64, reviewContent string) error {
-	return r.db.Model(&model.Interview{}).Where("id = ?", id).
-		Update("review_content", reviewContent).Error
+func (r *InterviewRepository) UpdateReview(userID, id int64, reviewContent string) error {
+	result := r.db.Model(&model.Interview{}).Where("id = ? AND user_id = ?", id, userID).
+		Update("review_content", reviewContent)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 && !r.exists(userID, id) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete 删除面试及其复盘评论、联系人关联和提醒（包括已发送的），子表先删以满足外键约束
+func (r *InterviewRepository) Delete(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var interview model.Interview
+		if err := tx.Select("id").Where("user_id = ?", userID).First(&interview, id).Error; err != nil {
+			return err
+		}
+
+		if err := deleteCommentsByInterviews(tx, []int64{id}); err != nil {
+			return err
+		}
+		if err := tx.Where("interview_id = ?", id).Delete(&model.ContactInterview{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("interview_id = ?", id).Delete(&model.InterviewReminder{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Interview{}, id).Error
+	})
 }
 
-func (r *InterviewRepository) Delete(id int64) error {
-	return r.db.Delete(&model.Interview{}, id).Error
+func (r *InterviewRepository) exists(userID, id int64) bool {
+	var count int64
+	r.db.Model(&model.Interview{}).Where("id = ? AND user_id = ?", id, userID).Count(&count)
+	return count > 0
 }
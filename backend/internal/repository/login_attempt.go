@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: database.GetDB()}
+}
+
+// Find 返回计数器，不存在时返回零值记录
+func (r *LoginAttemptRepository) Find(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := r.db.Where("`key` = ?", key).Limit(1).Find(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	attempt.Key = key
+	return &attempt, nil
+}
+
+// Update 在行锁内读取、修改并保存计数器，多个实例并发记录失败时不会丢失计数
+func (r *LoginAttemptRepository) Update(key string, fn func(*model.LoginAttempt)) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.LoginAttempt{Key: key}).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("`key` = ?", key).First(&attempt).Error; err != nil {
+			return err
+		}
+		fn(&attempt)
+		return tx.Save(&attempt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) Delete(key string) error {
+	return r.db.Where("`key` = ?", key).Delete(&model.LoginAttempt{}).Error
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type OfferRepository struct {
+	db *gorm.DB
+}
+
+func NewOfferRepository() *OfferRepository {
+	return &OfferRepository{db: database.GetDB()}
+}
+
+// FindByApplication 返回申请的 offer，谈判记录按时间顺序排列
+func (r *OfferRepository) FindByApplication(userID, appID int64) (*model.Offer, error) {
+	var offer model.Offer
+	err := r.db.Preload("Negotiations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("occurred_at ASC, id ASC")
+	}).Where("application_id = ? AND user_id = ?", appID, userID).First(&offer).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range offer.Negotiations {
+		offer.Negotiations[i].ParseTerms()
+	}
+	return &offer, nil
+}
+
+// FindByID 返回 offer 及其所属申请，供提醒调度使用
+func (r *OfferRepository) FindByID(userID, id int64) (*model.Offer, error) {
+	var offer model.Offer
+	err := r.db.Preload("Application").Where("user_id = ?", userID).First(&offer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+func (r *OfferRepository) Create(offer *model.Offer) error {
+	return r.db.Create(offer).Error
+}
+
+func (r *OfferRepository) Update(offer *model.Offer) error {
+	return r.db.Model(offer).Where("user_id = ?", offer.UserID).Updates(map[string]interface{}{
+		"status":      offer.Status,
+		"received_at": offer.ReceivedAt,
+		"expires_at":  offer.ExpiresAt,
+		"decided_at":  offer.DecidedAt,
+		"notes":       offer.Notes,
+	}).Error
+}
+
+// Delete 删除申请的 offer 及其谈判记录和提醒
+func (r *OfferRepository) Delete(userID, appID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var offer model.Offer
+		if err := tx.Where("application_id = ? AND user_id = ?", appID, userID).First(&offer).Error; err != nil {
+			return err
+		}
+		return deleteOffers(tx, []int64{offer.ID})
+	})
+}
+
+func (r *OfferRepository) CreateNegotiation(negotiation *model.OfferNegotiation) error {
+	return r.db.Create(negotiation).Error
+}
+
+func (r *OfferRepository) DeleteNegotiation(userID, offerID, id int64) error {
+	result := r.db.Where("offer_id = ? AND user_id = ?", offerID, userID).Delete(&model.OfferNegotiation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindExpiring 返回用户在 [start, end] 内截止、仍待答复的 offer，按截止时间排序
+func (r *OfferRepository) FindExpiring(userID int64, start, end time.Time) ([]model.Offer, error) {
+	offers := []model.Offer{}
+	err := r.db.Preload("Application").
+		Where("user_id = ? AND status = ? AND expires_at >= ? AND expires_at <= ?", userID, model.OfferStatusPending, start, end).
+		Order("expires_at ASC").
+		Find(&offers).Error
+	return offers, err
+}
+
+// FindPendingExpiringBetween 返回所有用户在 (start, end] 内截止、仍待答复的 offer
+func (r *OfferRepository) FindPendingExpiringBetween(start, end time.Time) ([]model.Offer, error) {
+	var offers []model.Offer
+	err := r.db.Where("status = ? AND expires_at > ? AND expires_at <= ?", model.OfferStatusPending, start, end).
+		Order("expires_at ASC").
+		Find(&offers).Error
+	return offers, err
+}
+
+// deleteOffers 删除 offer 及其谈判记录和提醒
+func deleteOffers(tx *gorm.DB, offerIDs []int64) error {
+	if len(offerIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("offer_id IN ?", offerIDs).Delete(&model.OfferNegotiation{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("offer_id IN ?", offerIDs).Delete(&model.OfferReminder{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&model.Offer{}, offerIDs).Error
+}
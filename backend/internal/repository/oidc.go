@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type OIDCStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCStateRepository() *OIDCStateRepository {
+	return &OIDCStateRepository{db: database.GetDB()}
+}
+
+func (r *OIDCStateRepository) Create(state *model.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState 标记 state 已回调并返回记录；不存在、已过期或已回调过时返回 gorm.ErrRecordNotFound
+func (r *OIDCStateRepository) ConsumeState(stateHash string, now time.Time) (*model.OIDCLoginState, error) {
+	result := r.db.Model(&model.OIDCLoginState{}).
+		Where("state_hash = ? AND callback_at IS NULL AND expires_at > ?", stateHash, now).
+		Update("callback_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var state model.OIDCLoginState
+	if err := r.db.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SetHandoff 记录登录成功的用户和换取 token 的一次性 code
+func (r *OIDCStateRepository) SetHandoff(id, userID int64, handoffHash string, expiresAt time.Time) error {
+	return r.db.Model(&model.OIDCLoginState{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"user_id":      userID,
+			"handoff_hash": handoffHash,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// ConsumeHandoff 消费一次性 code，返回对应的用户 ID；无效时返回 gorm.ErrRecordNotFound
+func (r *OIDCStateRepository) ConsumeHandoff(handoffHash string, now time.Time) (int64, error) {
+	result := r.db.Model(&model.OIDCLoginState{}).
+		Where("handoff_hash = ? AND handoff_used_at IS NULL AND expires_at > ? AND user_id > 0", handoffHash, now).
+		Update("handoff_used_at", now)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+
+	var state model.OIDCLoginState
+	if err := r.db.Where("handoff_hash = ?", handoffHash).First(&state).Error; err != nil {
+		return 0, err
+	}
+	return state.UserID, nil
+}
+
+// DeleteExpired 清理过期的登录记录
+func (r *OIDCStateRepository) DeleteExpired(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&model.OIDCLoginState{}).Error
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository() *ReminderRepository {
+	return &ReminderRepository{db: database.GetDB()}
+}
+
+// Ensure 插入提醒记录，已存在时忽略
+func (r *ReminderRepository) Ensure(reminder *model.InterviewReminder) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder).Error
+}
+
+// FindDue 查找已到期待发送的提醒，以及租约过期（进程中途退出）的发送中提醒
+func (r *ReminderRepository) FindDue(now time.Time, limit int) ([]model.InterviewReminder, error) {
+	var reminders []model.InterviewReminder
+	err := r.db.Where("(status = ? AND fire_at <= ?) OR (status = ? AND locked_until < ?)",
+		model.ReminderStatusPending, now, model.ReminderStatusSending, now).
+		Order("fire_at ASC").
+		Limit(limit).
+		Find(&reminders).Error
+	return reminders, err
+}
+
+// Claim 抢占一条提醒，返回是否抢占成功；多实例部署时只有一个实例能拿到
+func (r *ReminderRepository) Claim(id int64, now time.Time, lease time.Duration) (bool, error) {
+	return r.claim(&model.InterviewReminder{}, id, now, lease)
+}
+
+func (r *ReminderRepository) MarkSent(id int64, sentAt time.Time) error {
+	return r.markSent(&model.InterviewReminder{}, id, sentAt)
+}
+
+// MarkFailed 记录失败；final 为 false 时回到待发送，下次扫描重试
+func (r *ReminderRepository) MarkFailed(id int64, reason string, final bool) error {
+	return r.markFailed(&model.InterviewReminder{}, id, reason, final)
+}
+
+func (r *ReminderRepository) MarkSkipped(id int64, reason string) error {
+	return r.markSkipped(&model.InterviewReminder{}, id, reason)
+}
+
+// DeletePendingByInterview 删除面试尚未发送的提醒，面试改期、取消或删除时调用
+func (r *ReminderRepository) DeletePendingByInterview(interviewID int64) error {
+	return r.db.Where("interview_id = ? AND status = ?", interviewID, model.ReminderStatusPending).
+		Delete(&model.InterviewReminder{}).Error
+}
+
+// EnsureOffer 插入 offer 截止提醒记录，已存在时忽略
+func (r *ReminderRepository) EnsureOffer(reminder *model.OfferReminder) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder).Error
+}
+
+// FindDueOffers 与 FindDue 相同，查找到期的 offer 截止提醒
+func (r *ReminderRepository) FindDueOffers(now time.Time, limit int) ([]model.OfferReminder, error) {
+	var reminders []model.OfferReminder
+	err := r.db.Where("(status = ? AND fire_at <= ?) OR (status = ? AND locked_until < ?)",
+		model.ReminderStatusPending, now, model.ReminderStatusSending, now).
+		Order("fire_at ASC").
+		Limit(limit).
+		Find(&reminders).Error
+	return reminders, err
+}
+
+func (r *ReminderRepository) ClaimOffer(id int64, now time.Time, lease time.Duration) (bool, error) {
+	return r.claim(&model.OfferReminder{}, id, now, lease)
+}
+
+func (r *ReminderRepository) MarkOfferSent(id int64, sentAt time.Time) error {
+	return r.markSent(&model.OfferReminder{}, id, sentAt)
+}
+
+func (r *ReminderRepository) MarkOfferFailed(id int64, reason string, final bool) error {
+	return r.markFailed(&model.OfferReminder{}, id, reason, final)
+}
+
+func (r *ReminderRepository) MarkOfferSkipped(id int64, reason string) error {
+	return r.markSkipped(&model.OfferReminder{}, id, reason)
+}
+
+// DeletePendingByOffer 删除 offer 尚未发送的截止提醒，截止时间修改或 offer 已答复时调用
+func (r *ReminderRepository) DeletePendingByOffer(offerID int64) error {
+	return r.db.Where("offer_id = ? AND status = ?", offerID, model.ReminderStatusPending).
+		Delete(&model.OfferReminder{}).Error
+}
+
+// claim 等方法由面试提醒和 offer 提醒共用，table 为对应的模型
+func (r *ReminderRepository) claim(table interface{}, id int64, now time.Time, lease time.Duration) (bool, error) {
+	lockedUntil := now.Add(lease)
+	result := r.db.Model(table).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND locked_until < ?)",
+			model.ReminderStatusPending, model.ReminderStatusSending, now).
+		Updates(map[string]interface{}{
+			"status":       model.ReminderStatusSending,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": lockedUntil,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *ReminderRepository) markSent(table interface{}, id int64, sentAt time.Time) error {
+	return r.db.Model(table).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       model.ReminderStatusSent,
+			"sent_at":      sentAt,
+			"locked_until": nil,
+			"last_error":   "",
+		}).Error
+}
+
+func (r *ReminderRepository) markFailed(table interface{}, id int64, reason string, final bool) error {
+	status := model.ReminderStatusPending
+	if final {
+		status = model.ReminderStatusFailed
+	}
+	return r.db.Model(table).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"locked_until": nil,
+			"last_error":   truncate(reason, 500),
+		}).Error
+}
+
+func (r *ReminderRepository) markSkipped(table interface{}, id int64, reason string) error {
+	return r.db.Model(table).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       model.ReminderStatusSkipped,
+			"locked_until": nil,
+			"last_error":   truncate(reason, 500),
+		}).Error
+}
+
+// truncate 按字节截断且不切断 UTF-8 字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{db: database.GetDB()}
+}
+
+// Create 创建会话并写入第一枚 refresh token；token 为 nil 时会话不可刷新（管理员只读代登录）
+func (r *SessionRepository) Create(session *model.AuthSession, token *model.RefreshToken) error {
+	session.UserAgent = truncate(session.UserAgent, 255)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		if token == nil {
+			return nil
+		}
+		token.SessionID = session.ID
+		token.UserID = session.UserID
+		return tx.Create(token).Error
+	})
+}
+
+func (r *SessionRepository) FindByID(id int64) (*model.AuthSession, error) {
+	var session model.AuthSession
+	err := r.db.First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) FindRefreshToken(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate 将旧 refresh token 标记为已使用并签发下一枚，同时顺延会话有效期。
+// 旧令牌已被并发请求抢先使用时返回 false，调用方应按重放处理
+func (r *SessionRepository) Rotate(old, next *model.RefreshToken, now time.Time) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", old.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		next.SessionID = old.SessionID
+		next.UserID = old.UserID
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.AuthSession{}).Where("id = ?", old.SessionID).
+			Updates(map[string]interface{}{
+				"expires_at":   next.ExpiresAt,
+				"last_seen_at": now,
+			}).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// Revoke 吊销会话，已吊销的会话保留最初的原因
+func (r *SessionRepository) Revoke(id int64, reason string, now time.Time) error {
+	return r.db.Model(&model.AuthSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+		}).Error
+}
+
+// IsActive 判断会话是否属于该用户且未过期、未吊销
+func (r *SessionRepository) IsActive(id, userID int64, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.AuthSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", id, userID, now).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindActiveByUser 返回用户未过期、未吊销的会话，最近活跃的在前
+func (r *SessionRepository) FindActiveByUser(userID int64, now time.Time) ([]model.AuthSession, error) {
+	var sessions []model.AuthSession
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeForUser 吊销用户的某个会话，会话不存在或已失效时返回 gorm.ErrRecordNotFound
+func (r *SessionRepository) RevokeForUser(userID, id int64, reason string, now time.Time) error {
+	result := r.db.Model(&model.AuthSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeOthers 吊销除 keepID 之外的全部会话，返回吊销数量
+func (r *SessionRepository) RevokeOthers(userID, keepID int64, reason string, now time.Time) (int64, error) {
+	result := r.db.Model(&model.AuthSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Updates(map[string]interface{}{
+			"revoked_at":    now,
+			"revoke_reason": reason,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Touch 更新最近活跃时间和 IP；只有距上次记录超过 interval 才真正写库，避免每个请求都更新
+func (r *SessionRepository) Touch(id int64, ip string, now time.Time, interval time.Duration) error {
+	return r.db.Model(&model.AuthSession{}).
+		Where("id = ? AND (last_seen_at < ? OR ip <> ?)", id, now.Add(-interval), ip).
+		Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"ip":           ip,
+		}).Error
+}
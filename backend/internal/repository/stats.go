@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+// StatsRepository 用 SQL 聚合计算统计数据，避免把全部记录下发到前端
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository() *StatsRepository {
+	return &StatsRepository{db: database.GetDB()}
+}
+
+// DateRange 为空的一端表示不限制
+type DateRange struct {
+	Start *time.Time
+	End   *time.Time
+}
+
+func (r *StatsRepository) applications(userID int64, rng DateRange) *gorm.DB {
+	query := r.db.Model(&model.Application{}).Where("applications.user_id = ?", userID)
+	if rng.Start != nil {
+		query = query.Where("applications.created_at >= ?", *rng.Start)
+	}
+	if rng.End != nil {
+		query = query.Where("applications.created_at <= ?", *rng.End)
+	}
+	return query
+}
+
+func (r *StatsRepository) interviews(userID int64, rng DateRange) *gorm.DB {
+	query := r.db.Model(&model.Interview{}).Where("interviews.user_id = ?", userID)
+	if rng.Start != nil {
+		query = query.Where("interviews.start_time >= ?", *rng.Start)
+	}
+	if rng.End != nil {
+		query = query.Where("interviews.start_time <= ?", *rng.End)
+	}
+	return query
+}
+
+// StatusCounts 按当前状态统计申请数量，申请按创建时间过滤
+func (r *StatsRepository) StatusCounts(userID int64, rng DateRange) ([]model.StatusCount, error) {
+	var counts []model.StatusCount
+	err := r.applications(userID, rng).
+		Select("current_status AS status, COUNT(*) AS count").
+		Group("current_status").
+		Order("count DESC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// InterviewCounts 返回面试总数、已完成数，以及已完成且写了复盘的数量
+func (r *StatsRepository) InterviewCounts(userID int64, rng DateRange) (total, finished, reviewed int64, err error) {
+	var row struct {
+		Total    int64
+		Finished int64
+		Reviewed int64
+	}
+	err = r.interviews(userID, rng).
+		Select(`COUNT(*) AS total,
+			COALESCE(SUM(status = ?), 0) AS finished,
+			COALESCE(SUM(status = ? AND TRIM(COALESCE(review_content, '')) <> ''), 0) AS reviewed`,
+			model.InterviewStatusFinished, model.InterviewStatusFinished).
+		Scan(&row).Error
+	return row.Total, row.Finished, row.Reviewed, err
+}
+
+// InterviewsByWeek 按周（以周一为起点）统计面试数量
+func (r *StatsRepository) InterviewsByWeek(userID int64, rng DateRange) ([]model.PeriodCount, error) {
+	var counts []model.PeriodCount
+	err := r.interviews(userID, rng).
+		Select("DATE_FORMAT(DATE_SUB(DATE(start_time), INTERVAL WEEKDAY(start_time) DAY), '%Y-%m-%d') AS period, COUNT(*) AS count").
+		Group("period").
+		Order("period ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// InterviewsByMonth 按月统计面试数量
+func (r *StatsRepository) InterviewsByMonth(userID int64, rng DateRange) ([]model.PeriodCount, error) {
+	var counts []model.PeriodCount
+	err := r.interviews(userID, rng).
+		Select("DATE_FORMAT(start_time, '%Y-%m') AS period, COUNT(*) AS count").
+		Group("period").
+		Order("period ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// RoundDistribution 按轮次名称统计面试数量
+func (r *StatsRepository) RoundDistribution(userID int64, rng DateRange) ([]model.RoundCount, error) {
+	var counts []model.RoundCount
+	err := r.interviews(userID, rng).
+		Select("round_name, COUNT(*) AS count").
+		Group("round_name").
+		Order("count DESC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// offered 筛选曾经拿到 Offer 的申请，之后拒绝或被撤回的也算在内。
+// Offer 时间取状态流转记录中第一次进入 OFFER 的时间；没有流转记录的历史数据按当前状态判断
+func (r *StatsRepository) offered(userID int64, rng DateRange) *gorm.DB {
+	offers := r.db.Model(&model.ApplicationStatusEvent{}).
+		Select("application_id, MIN(created_at) AS offered_at").
+		Where("user_id = ? AND to_status = ?", userID, model.StatusOffer).
+		Group("application_id")
+
+	return r.applications(userID, rng).
+		Joins("LEFT JOIN (?) AS offers ON offers.application_id = applications.id", offers).
+		Where("applications.current_status = ? OR offers.offered_at IS NOT NULL", model.StatusOffer)
+}
+
+// OfferCount 统计曾经拿到 Offer 的申请数量
+func (r *StatsRepository) OfferCount(userID int64, rng DateRange) (int64, error) {
+	var count int64
+	err := r.offered(userID, rng).Count(&count).Error
+	return count, err
+}
+
+// AvgDaysToOffer 计算从创建申请到拿到 Offer 的平均天数，没有流转记录的历史数据退化为 updated_at
+func (r *StatsRepository) AvgDaysToOffer(userID int64, rng DateRange) (*float64, error) {
+	var avg *float64
+	err := r.offered(userID, rng).
+		Select("AVG(TIMESTAMPDIFF(SECOND, applications.created_at, COALESCE(offers.offered_at, applications.updated_at))) / 86400").
+		Scan(&avg).Error
+	return avg, err
+}
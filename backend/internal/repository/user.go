@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 	"offermatrix/internal/model"
 	"offermatrix/pkg/database"
@@ -41,3 +43,360 @@ func (r *UserRepository) ExistsByUsername(username string) bool {
 	r.db.Model(&model.User{}).Where("username = ?", username).Count(&count)
 	return count > 0
 }
+
+// FindByCalendarToken 按订阅密钥的摘要查找用户，已停用的账号查不到
+func (r *UserRepository) FindByCalendarToken(tokenHash string) (*model.User, error) {
+	var user model.User
+	err := r.db.Where("calendar_token_hash = ? AND calendar_token_hash <> '' AND disabled_at IS NULL", tokenHash).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateCalendarToken 保存订阅密钥的摘要，传空字符串表示关闭订阅
+func (r *UserRepository) UpdateCalendarToken(id int64, tokenHash string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("calendar_token_hash", tokenHash).Error
+}
+
+func (r *UserRepository) UpdateEmail(id int64, email string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("email", email).Error
+}
+
+// FindByEmail 邮箱不唯一，可能对应多个账号
+func (r *UserRepository) FindByEmail(email string) ([]model.User, error) {
+	var users []model.User
+	err := r.db.Where("email = ?", email).Find(&users).Error
+	return users, err
+}
+
+func (r *UserRepository) UpdatePassword(id int64, hashedPassword string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+}
+
+func (r *UserRepository) CreatePasswordReset(token *model.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *UserRepository) FindPasswordReset(hash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ResetPassword 消费找回密码令牌并设置新密码，同时作废该用户其余未使用的令牌、吊销全部会话。
+// 令牌已被使用或已过期时返回 false
+func (r *UserRepository) ResetPassword(token *model.PasswordResetToken, hashedPassword string, now time.Time) (bool, error) {
+	reset := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", token.UserID).
+			Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.AuthSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Updates(map[string]interface{}{
+				"revoked_at":    now,
+				"revoke_reason": model.SessionRevokedPassword,
+			}).Error; err != nil {
+			return err
+		}
+		reset = true
+		return nil
+	})
+	return reset, err
+}
+
+// Delete 注销账号，在一个事务中删除用户及其名下的全部数据
+func (r *UserRepository) Delete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 评论要在面试之前清理：自己面试下的评论（含他人发表的），以及自己在别人面试下发表的评论
+		if err := deleteCommentsByInterviews(tx, tx.Model(&model.Interview{}).Select("id").Where("user_id = ?", id)); err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN (?)", tx.Model(&model.ReviewComment{}).Select("id").Where("author_id = ?", id)).
+			Delete(&model.ReviewCommentMention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("author_id = ?", id).Delete(&model.ReviewComment{}).Error; err != nil {
+			return err
+		}
+
+		owned := []interface{}{
+			&model.ApplicationStatusEvent{},
+			&model.InterviewReminder{},
+			&model.Interview{},
+			&model.Compensation{},
+			&model.OfferScore{},
+			&model.OfferCriterion{},
+			&model.OfferReminder{},
+			&model.OfferNegotiation{},
+			&model.Offer{},
+			&model.Application{},
+			&model.ContactInteraction{},
+			&model.ContactApplication{},
+			&model.ContactInterview{},
+			&model.Contact{},
+			&model.WebhookDelivery{},
+			&model.WebhookSubscription{},
+			&model.RefreshToken{},
+			&model.AuthSession{},
+			&model.PasswordResetToken{},
+			&model.RecoveryCode{},
+			&model.APIToken{},
+			&model.WorkspaceMember{},
+			&model.ReviewCommentMention{},
+			&model.ReviewCommentRead{},
+		}
+		for _, m := range owned {
+			if err := tx.Where("user_id = ?", id).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		// 自己的工作区连同其中的成员一并删除
+		if err := tx.Where("workspace_id IN (?)", tx.Model(&model.Workspace{}).Select("id").Where("owner_id = ?", id)).
+			Delete(&model.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("owner_id = ?", id).Delete(&model.Workspace{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&model.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// SetTOTPSecret 保存待确认的密钥，此时两步验证尚未启用
+func (r *UserRepository) SetTOTPSecret(id int64, secret string) error {
+	return r.db.Model(&model.User{}).Where("id = ? AND totp_enabled = ?", id, false).
+		Updates(map[string]interface{}{
+			"totp_secret":       secret,
+			"totp_last_counter": 0,
+		}).Error
+}
+
+// EnableTOTP 启用两步验证并替换恢复码
+func (r *UserRepository) EnableTOTP(id, counter int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"totp_enabled":      true,
+				"totp_last_counter": counter,
+			}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, id, codeHashes)
+	})
+}
+
+// DisableTOTP 关闭两步验证，清除密钥和恢复码
+func (r *UserRepository) DisableTOTP(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"totp_enabled":      false,
+				"totp_secret":       "",
+				"totp_last_counter": 0,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", id).Delete(&model.RecoveryCode{}).Error
+	})
+}
+
+// AdvanceTOTPCounter 记录已使用的时间步，不大于上次记录的验证码视为重放，返回 false
+func (r *UserRepository) AdvanceTOTPCounter(id, counter int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND totp_last_counter < ?", id, counter).
+		Update("totp_last_counter", counter)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *UserRepository) ReplaceRecoveryCodes(id int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, id, codeHashes)
+	})
+}
+
+// UseRecoveryCode 消费一枚未使用的恢复码，不存在或已使用时返回 false
+func (r *UserRepository) UseRecoveryCode(id int64, codeHash string, now time.Time) (bool, error) {
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", id, codeHash).
+		Limit(1).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *UserRepository) CountRecoveryCodes(id int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", id).
+		Count(&count).Error
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID int64, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]model.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+func (r *UserRepository) FindByOIDCSubject(issuer, subject string) (*model.User, error) {
+	var user model.User
+	err := r.db.Where("oidc_issuer = ? AND oidc_subject = ?", issuer, subject).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LinkOIDC 把企业身份关联到已有账号
+func (r *UserRepository) LinkOIDC(id int64, issuer, subject string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"oidc_issuer":  issuer,
+			"oidc_subject": subject,
+		}).Error
+}
+
+// List 分页查询用户，keyword 匹配用户名或邮箱
+func (r *UserRepository) List(keyword string, offset, limit int) ([]model.User, int64, error) {
+	query := r.db.Model(&model.User{})
+	if keyword != "" {
+		query = query.Where("username LIKE ? OR email LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	err := query.Order("id ASC").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+func (r *UserRepository) UpdateRole(id int64, role string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+// PromoteAdmins 把配置中列出的用户名设为管理员，返回实际变更的数量
+func (r *UserRepository) PromoteAdmins(usernames []string) (int64, error) {
+	if len(usernames) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&model.User{}).
+		Where("username IN ? AND role <> ?", usernames, model.RoleAdmin).
+		Update("role", model.RoleAdmin)
+	return result.RowsAffected, result.Error
+}
+
+// Disable 停用账号，同时吊销全部会话和个人访问令牌，已签发的 token 随之失效
+func (r *UserRepository) Disable(id int64, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// 日历订阅密钥同样是长期凭证，停用时一并作废
+		if err := tx.Model(&model.User{}).Where("id = ? AND disabled_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"disabled_at":    now,
+				"calendar_token_hash": "",
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.AuthSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"revoked_at":    now,
+				"revoke_reason": model.SessionRevokedDisabled,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.APIToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+	})
+}
+
+// Enable 恢复账号；停用时吊销的会话、令牌和日历订阅不会恢复，需要重新登录并重新生成订阅地址
+func (r *UserRepository) Enable(id int64) error {
+	return r.db.Model(&model.User{}).Where("id = ?", id).Update("disabled_at", nil).Error
+}
+
+// SetPassword 由管理员重置密码，并吊销该用户的全部会话
+func (r *UserRepository) SetPassword(id int64, hashedPassword string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", id).
+			Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.AuthSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"revoked_at":    now,
+				"revoke_reason": model.SessionRevokedPassword,
+			}).Error
+	})
+}
+
+// Usage 统计用户名下的数据量，有效会话和令牌只计未吊销且未过期的
+func (r *UserRepository) Usage(id int64, now time.Time) (*model.UserUsage, error) {
+	var usage model.UserUsage
+	counts := []struct {
+		model interface{}
+		where string
+		args  []interface{}
+		dest  *int64
+	}{
+		{&model.Application{}, "user_id = ?", []interface{}{id}, &usage.Applications},
+		{&model.Interview{}, "user_id = ?", []interface{}{id}, &usage.Interviews},
+		{&model.WebhookSubscription{}, "user_id = ?", []interface{}{id}, &usage.Webhooks},
+		{&model.APIToken{}, "user_id = ? AND revoked_at IS NULL AND expires_at > ?", []interface{}{id, now}, &usage.APITokens},
+		{&model.AuthSession{}, "user_id = ? AND revoked_at IS NULL AND expires_at > ?", []interface{}{id, now}, &usage.ActiveSessions},
+	}
+	for _, c := range counts {
+		if err := r.db.Model(c.model).Where(c.where, c.args...).Count(c.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var lastSeen struct{ LastSeenAt *time.Time }
+	if err := r.db.Model(&model.AuthSession{}).Select("MAX(last_seen_at) AS last_seen_at").
+		Where("user_id = ?", id).Scan(&lastSeen).Error; err != nil {
+		return nil, err
+	}
+	usage.LastSeenAt = lastSeen.LastSeenAt
+	return &usage, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{db: database.GetDB()}
+}
+
+func (r *WebhookRepository) Create(sub *model.WebhookSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+func (r *WebhookRepository) FindAll(userID int64) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+
+func (r *WebhookRepository) FindActive(userID int64) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.db.Where("user_id = ? AND active = ?", userID, true).Find(&subs).Error
+	return subs, err
+}
+
+func (r *WebhookRepository) FindByID(userID, id int64) (*model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	err := r.db.Where("user_id = ?", userID).First(&sub, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) Update(sub *model.WebhookSubscription) error {
+	return r.db.Model(sub).Where("user_id = ?", sub.UserID).Updates(map[string]interface{}{
+		"url":         sub.URL,
+		"events":      sub.Events,
+		"description": sub.Description,
+		"active":      sub.Active,
+	}).Error
+}
+
+// Delete 删除订阅及其投递记录
+func (r *WebhookRepository) Delete(userID, id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&model.WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("subscription_id = ?", id).Delete(&model.WebhookDelivery{}).Error
+	})
+}
+
+func (r *WebhookRepository) CreateDelivery(delivery *model.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *WebhookRepository) FindDeliveries(userID, subscriptionID int64, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.Where("user_id = ? AND subscription_id = ?", userID, subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *WebhookRepository) FindDelivery(userID, id int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.db.Where("user_id = ?", userID).First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDueDeliveries 查找到期待投递的记录，以及租约过期的投递中记录
+func (r *WebhookRepository) FindDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND locked_until < ?)",
+		model.DeliveryStatusPending, now, model.DeliveryStatusSending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery 抢占一条投递，返回是否成功
+func (r *WebhookRepository) ClaimDelivery(id int64, now time.Time, lease time.Duration) (bool, error) {
+	result := r.db.Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND locked_until < ?)",
+			model.DeliveryStatusPending, model.DeliveryStatusSending, now).
+		Updates(map[string]interface{}{
+			"status":       model.DeliveryStatusSending,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": now.Add(lease),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// FinishDelivery 记录一次投递结果；nextAttempt 为空表示不再重试
+func (r *WebhookRepository) FinishDelivery(id int64, status int, lastError string, succeeded bool, nextAttempt *time.Time) error {
+	updates := map[string]interface{}{
+		"response_status": status,
+		"last_error":      truncate(lastError, 500),
+		"locked_until":    nil,
+	}
+	switch {
+	case succeeded:
+		updates["status"] = model.DeliveryStatusSucceeded
+		updates["delivered_at"] = time.Now()
+	case nextAttempt != nil:
+		updates["status"] = model.DeliveryStatusPending
+		updates["next_attempt_at"] = *nextAttempt
+	default:
+		updates["status"] = model.DeliveryStatusFailed
+	}
+	return r.db.Model(&model.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Redeliver 将投递重置为立即重试
+func (r *WebhookRepository) Redeliver(userID, id int64) error {
+	result := r.db.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND user_id = ? AND status <> ?", id, userID, model.DeliveryStatusSending).
+		Updates(map[string]interface{}{
+			"status":          model.DeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"offermatrix/internal/model"
+	"offermatrix/pkg/database"
+)
+
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository() *WorkspaceRepository {
+	return &WorkspaceRepository{db: database.GetDB()}
+}
+
+func (r *WorkspaceRepository) FindByOwner(ownerID int64) (*model.Workspace, error) {
+	var ws model.Workspace
+	err := r.db.Where("owner_id = ?", ownerID).First(&ws).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ws, nil
+}
+
+// EnsureForOwner 返回求职者的工作区，不存在时创建
+func (r *WorkspaceRepository) EnsureForOwner(ownerID int64, name string) (*model.Workspace, error) {
+	ws := model.Workspace{OwnerID: ownerID, Name: name}
+	err := r.db.Where("owner_id = ?", ownerID).FirstOrCreate(&ws).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ws, nil
+}
+
+// memberRow 成员记录连同对方用户名
+type memberRow struct {
+	model.WorkspaceMember
+	Username string
+}
+
+// FindMembers 返回工作区的成员和邀请，附带成员用户名
+func (r *WorkspaceRepository) FindMembers(workspaceID int64) ([]model.WorkspaceMemberResponse, error) {
+	var rows []memberRow
+	err := r.db.Table("workspace_members AS m").
+		Select("m.*, u.username").
+		Joins("JOIN users AS u ON u.id = m.user_id").
+		Where("m.workspace_id = ?", workspaceID).
+		Order("m.created_at ASC, m.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]model.WorkspaceMemberResponse, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, model.WorkspaceMemberResponse{
+			ID:          row.ID,
+			UserID:      row.UserID,
+			Username:    row.Username,
+			Permissions: row.PermissionList(),
+			Status:      row.Status,
+			RespondedAt: row.RespondedAt,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+	return members, nil
+}
+
+func (r *WorkspaceRepository) FindMember(workspaceID, id int64) (*model.WorkspaceMember, error) {
+	var member model.WorkspaceMember
+	err := r.db.Where("workspace_id = ?", workspaceID).First(&member, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Invite 创建邀请；对方此前拒绝、被撤销或已退出时复用原记录重新邀请。
+// 邀请仍待处理或对方已是成员时返回 false
+func (r *WorkspaceRepository) Invite(member *model.WorkspaceMember) (bool, error) {
+	invited := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.WorkspaceMember
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			invited = true
+			return tx.Create(member).Error
+		}
+		if err != nil {
+			return err
+		}
+		if existing.Status == model.MemberPending || existing.Status == model.MemberAccepted {
+			return nil
+		}
+
+		invited = true
+		if err := tx.Model(&existing).Updates(map[string]interface{}{
+			"permissions":  member.Permissions,
+			"status":       model.MemberPending,
+			"invited_by":   member.InvitedBy,
+			"responded_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.First(member, existing.ID).Error
+	})
+	return invited, err
+}
+
+func (r *WorkspaceRepository) UpdatePermissions(workspaceID, id int64, permissions string) error {
+	return r.db.Model(&model.WorkspaceMember{}).
+		Where("id = ? AND workspace_id = ?", id, workspaceID).
+		Update("permissions", permissions).Error
+}
+
+// Transition 在成员当前状态属于 from 时改为 to，状态不符或记录不属于 userID 时返回 gorm.ErrRecordNotFound。
+// userID 为 0 表示由工作区所有者操作，改用 workspaceID 校验归属
+func (r *WorkspaceRepository) Transition(id, workspaceID, userID int64, from []string, to string, now time.Time) error {
+	query := r.db.Model(&model.WorkspaceMember{}).Where("id = ? AND status IN ?", id, from)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	} else {
+		query = query.Where("workspace_id = ?", workspaceID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":       to,
+		"responded_at": now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// membershipRow 成员记录连同工作区及其所有者
+type membershipRow struct {
+	model.WorkspaceMember
+	WorkspaceName string
+	OwnerID       int64
+	OwnerUsername string
+}
+
+// FindMemberships 返回用户收到的邀请和加入的工作区，不含已结束的记录
+func (r *WorkspaceRepository) FindMemberships(userID int64) ([]model.MembershipResponse, error) {
+	var rows []membershipRow
+	err := r.db.Table("workspace_members AS m").
+		Select("m.*, w.name AS workspace_name, w.owner_id, u.username AS owner_username").
+		Joins("JOIN workspaces AS w ON w.id = m.workspace_id").
+		Joins("JOIN users AS u ON u.id = w.owner_id").
+		Where("m.user_id = ? AND m.status IN ?", userID, []string{model.MemberPending, model.MemberAccepted}).
+		Order("m.created_at DESC, m.id DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := make([]model.MembershipResponse, 0, len(rows))
+	for _, row := range rows {
+		memberships = append(memberships, model.MembershipResponse{
+			ID:            row.ID,
+			WorkspaceID:   row.WorkspaceID,
+			WorkspaceName: row.WorkspaceName,
+			OwnerID:       row.OwnerID,
+			OwnerUsername: row.OwnerUsername,
+			Permissions:   row.PermissionList(),
+			Status:        row.Status,
+			RespondedAt:   row.RespondedAt,
+			CreatedAt:     row.CreatedAt,
+		})
+	}
+	return memberships, nil
+}
+
+// FindAccess 返回工作区以及用户在其中已接受的成员记录；用户是工作区所有者时成员记录为 nil
+func (r *WorkspaceRepository) FindAccess(workspaceID, userID int64) (*model.Workspace, *model.WorkspaceMember, error) {
+	var ws model.Workspace
+	if err := r.db.First(&ws, workspaceID).Error; err != nil {
+		return nil, nil, err
+	}
+	if ws.OwnerID == userID {
+		return &ws, nil, nil
+	}
+
+	var member model.WorkspaceMember
+	err := r.db.Where("workspace_id = ? AND user_id = ? AND status = ?", workspaceID, userID, model.MemberAccepted).
+		First(&member).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ws, &member, nil
+}
@@ -0,0 +1,106 @@
+// Package webhook 把流水线事件写入持久化队列，并由后台 worker 以 HMAC 签名投递到用户配置的地址
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+)
+
+// Envelope 是投递给订阅方的 JSON 结构
+type Envelope struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type Dispatcher struct {
+	repo *repository.WebhookRepository
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{repo: repository.NewWebhookRepository()}
+}
+
+// Emit 为该用户所有订阅了 event 的回调入队。入队失败只记录日志，不影响业务请求
+func (d *Dispatcher) Emit(userID int64, event string, data interface{}) {
+	subs, err := d.repo.FindActive(userID)
+	if err != nil {
+		log.Printf("Failed to load webhooks for user %d: %v", userID, err)
+		return
+	}
+
+	var payload []byte
+	var eventID string
+	for i := range subs {
+		if !subs[i].Subscribes(event) {
+			continue
+		}
+		// 同一事件投递给多个订阅时共用 id 和内容
+		if payload == nil {
+			if eventID, payload, err = encode(event, data); err != nil {
+				log.Printf("Failed to encode webhook event %s: %v", event, err)
+				return
+			}
+		}
+		if err := d.enqueue(&subs[i], eventID, event, payload); err != nil {
+			log.Printf("Failed to enqueue webhook %d for %s: %v", subs[i].ID, event, err)
+		}
+	}
+}
+
+// EmitTo 向指定订阅入队一个事件，用于发送测试事件
+func (d *Dispatcher) EmitTo(sub *model.WebhookSubscription, event string, data interface{}) (*model.WebhookDelivery, error) {
+	eventID, payload, err := encode(event, data)
+	if err != nil {
+		return nil, err
+	}
+	delivery := &model.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		EventID:        eventID,
+		Event:          event,
+		Payload:        string(payload),
+		Status:         model.DeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+	}
+	return delivery, d.repo.CreateDelivery(delivery)
+}
+
+func (d *Dispatcher) enqueue(sub *model.WebhookSubscription, eventID, event string, payload []byte) error {
+	return d.repo.CreateDelivery(&model.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		EventID:        eventID,
+		Event:          event,
+		Payload:        string(payload),
+		Status:         model.DeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+	})
+}
+
+func encode(event string, data interface{}) (string, []byte, error) {
+	id := NewID()
+	payload, err := json.Marshal(Envelope{
+		ID:        id,
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	return id, payload, err
+}
+
+// NewID 生成随机十六进制 id
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
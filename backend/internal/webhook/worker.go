@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"offermatrix/internal/model"
+	"offermatrix/internal/repository"
+	"offermatrix/pkg/hmacsig"
+	"offermatrix/pkg/netguard"
+)
+
+const (
+	MaxAttempts  = 8
+	baseBackoff  = 30 * time.Second
+	maxBackoff   = 6 * time.Hour
+	claimLease   = 2 * time.Minute
+	pollInterval = 5 * time.Second
+	batchSize    = 50
+)
+
+// Worker 轮询投递队列并发送回调，失败按指数退避重试
+type Worker struct {
+	repo   *repository.WebhookRepository
+	client *http.Client
+}
+
+func NewWorker() *Worker {
+	return &Worker{
+		repo:   repository.NewWebhookRepository(),
+		client: netguard.NewClient(10 * time.Second),
+	}
+}
+
+// Run 持续处理队列直到 ctx 取消
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		deliveries, err := w.repo.FindDueDeliveries(now, batchSize)
+		if err != nil {
+			log.Printf("Webhook queue scan failed: %v", err)
+		}
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return
+			}
+			w.process(ctx, delivery, now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context, delivery model.WebhookDelivery, now time.Time) {
+	ok, err := w.repo.ClaimDelivery(delivery.ID, now, claimLease)
+	if err != nil || !ok {
+		return
+	}
+	attempt := delivery.Attempts + 1
+
+	sub, err := w.repo.FindByID(delivery.UserID, delivery.SubscriptionID)
+	if err != nil || !sub.Active {
+		w.finish(delivery.ID, 0, "subscription removed or disabled", false, nil)
+		return
+	}
+
+	status, err := w.send(ctx, sub, delivery)
+	if err == nil && status >= 200 && status < 300 {
+		w.finish(delivery.ID, status, "", true, nil)
+		return
+	}
+
+	lastError := fmt.Sprintf("unexpected status %d", status)
+	if err != nil {
+		lastError = err.Error()
+	}
+	var next *time.Time
+	if attempt < MaxAttempts {
+		t := time.Now().Add(Backoff(attempt))
+		next = &t
+	}
+	w.finish(delivery.ID, status, lastError, false, next)
+}
+
+// send 投递一次回调，只返回状态码：响应内容不保存，避免回调地址被用来读取内网服务的响应
+func (w *Worker) send(ctx context.Context, sub *model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader([]byte(delivery.Payload)))
+	if err != nil {
+		return 0, err
+	}
+
+	// 签名覆盖时间戳，接收方可据此拒绝重放
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OfferMatrix-Webhook/1.0")
+	req.Header.Set("X-OfferMatrix-Event", delivery.Event)
+	req.Header.Set("X-OfferMatrix-Delivery", delivery.EventID)
+	req.Header.Set("X-OfferMatrix-Timestamp", timestamp)
+	req.Header.Set("X-OfferMatrix-Signature", hmacsig.Sign(sub.Secret, []byte(timestamp+"."+delivery.Payload)))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// 读完少量响应以便复用连接
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, nil
+}
+
+func (w *Worker) finish(id int64, status int, lastError string, succeeded bool, next *time.Time) {
+	if err := w.repo.FinishDelivery(id, status, lastError, succeeded, next); err != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", id, err)
+	}
+}
+
+// Backoff 返回第 attempt 次失败后的等待时间：30s、1m、2m ... 最长 6h
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
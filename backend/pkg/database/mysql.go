@@ -22,7 +22,38 @@ func Init() error {
 	}
 
 	// Auto migrate tables
-	if err := db.AutoMigrate(&model.Application{}, &model.Interview{}, &model.User{}); err != nil {
+	if err := db.AutoMigrate(
+		&model.Application{},
+		&model.Interview{},
+		&model.User{},
+		&model.ApplicationStatusEvent{},
+		&model.InterviewReminder{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.AuthSession{},
+		&model.RefreshToken{},
+		&model.PasswordResetToken{},
+		&model.AuditLog{},
+		&model.LoginAttempt{},
+		&model.RecoveryCode{},
+		&model.APIToken{},
+		&model.OIDCLoginState{},
+		&model.Workspace{},
+		&model.WorkspaceMember{},
+		&model.ReviewComment{},
+		&model.ReviewCommentMention{},
+		&model.ReviewCommentRead{},
+		&model.Compensation{},
+		&model.OfferCriterion{},
+		&model.OfferScore{},
+		&model.Offer{},
+		&model.OfferNegotiation{},
+		&model.OfferReminder{},
+		&model.Contact{},
+		&model.ContactApplication{},
+		&model.ContactInterview{},
+		&model.ContactInteraction{},
+	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
@@ -0,0 +1,26 @@
+// Package hmacsig 生成和校验出站回调使用的 HMAC-SHA256 签名
+package hmacsig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const prefix = "sha256="
+
+// Sign 返回 "sha256=<hex>" 格式的签名
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 以常数时间比较签名，供接收方或测试使用
+func Verify(secret string, payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, prefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}
@@ -0,0 +1,126 @@
+// Package ical 实现 RFC 5545 iCalendar 的最小子集，用于导出面试日程
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateTimeUTC = "20060102T150405Z"
+	maxLineLen  = 75
+)
+
+// Event 对应一个 VEVENT
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string // TENTATIVE / CONFIRMED / CANCELLED
+	Start        time.Time
+	End          time.Time
+	Stamp        time.Time
+	LastModified time.Time
+}
+
+// Calendar 对应一个 VCALENDAR
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Marshal 将日历编码为 RFC 5545 文本（CRLF 换行、75 字节折行）
+func (c *Calendar) Marshal() []byte {
+	var buf bytes.Buffer
+	w := &writer{buf: &buf}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = "-//OfferMatrix//Interviews//CN"
+	}
+	w.line("PRODID", prodID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", EscapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", e.UID)
+		stamp := e.Stamp
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		w.line("DTSTAMP", formatUTC(stamp))
+		w.line("DTSTART", formatUTC(e.Start))
+		w.line("DTEND", formatUTC(e.End))
+		if !e.LastModified.IsZero() {
+			w.line("LAST-MODIFIED", formatUTC(e.LastModified))
+		}
+		w.line("SUMMARY", EscapeText(e.Summary))
+		if e.Description != "" {
+			w.line("DESCRIPTION", EscapeText(e.Description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION", EscapeText(e.Location))
+		}
+		if e.URL != "" {
+			w.line("URL", e.URL)
+		}
+		if e.Status != "" {
+			w.line("STATUS", e.Status)
+		}
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// EscapeText 按 RFC 5545 3.3.11 转义 TEXT 值
+func EscapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(dateTimeUTC)
+}
+
+type writer struct {
+	buf *bytes.Buffer
+}
+
+// line 写入一行内容，超过 75 字节时按 UTF-8 字符边界折行
+func (w *writer) line(name, value string) {
+	s := name + ":" + value
+	limit := maxLineLen
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// 续行开头的空格占用 1 字节
+		limit = maxLineLen - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
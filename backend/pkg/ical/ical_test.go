@@ -0,0 +1,89 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestMarshalFoldsLongLines(t *testing.T) {
+	cal := &Calendar{Name: "面试", Events: []Event{{
+		UID:         "1@offermatrix",
+		Summary:     strings.Repeat("字节跳动后端开发一面", 10),
+		Description: strings.Repeat("a", 200),
+		Start:       time.Date(2026, 2, 3, 2, 0, 0, 0, time.UTC),
+		End:         time.Date(2026, 2, 3, 3, 0, 0, 0, time.UTC),
+	}}}
+	out := cal.Marshal()
+
+	if !bytes.HasSuffix(out, []byte("\r\n")) {
+		t.Fatal("output does not end with CRLF")
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\r\n"), "\r\n")
+	folded := 0
+	for _, line := range lines {
+		if len(line) > maxLineLen {
+			t.Errorf("line exceeds %d bytes: %q", maxLineLen, line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line splits a UTF-8 sequence: %q", line)
+		}
+		if strings.HasPrefix(line, " ") {
+			folded++
+		}
+	}
+	if folded == 0 {
+		t.Fatal("long values were not folded")
+	}
+}
+
+func TestMarshalParseRoundTrip(t *testing.T) {
+	want := Event{
+		UID:         "42@offermatrix",
+		Summary:     "腾讯 · 终面; 技术, 主管",
+		Description: strings.Repeat("请提前 10 分钟进入会议室，携带简历。\n", 8) + `路径 C:\offer`,
+		Location:    "深圳市南山区科技园, 腾讯滨海大厦",
+		URL:         "https://meeting.example.com/j/123456789?pwd=abc",
+		Status:      "CONFIRMED",
+		Start:       time.Date(2026, 2, 3, 2, 0, 0, 0, time.UTC),
+		End:         time.Date(2026, 2, 3, 3, 30, 0, 0, time.UTC),
+	}
+	cal := &Calendar{Events: []Event{want}}
+
+	events, err := ParseEvents(bytes.NewReader(cal.Marshal()), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseEvents() error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	got := events[0]
+	if got.UID != want.UID || got.Summary != want.Summary || got.Description != want.Description ||
+		got.Location != want.Location || got.URL != want.URL || got.Status != want.Status {
+		t.Errorf("text fields changed in round trip:\n got %+v\nwant %+v", got, want)
+	}
+	if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+		t.Errorf("times = %v - %v, want %v - %v", got.Start, got.End, want.Start, want.End)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a,b;c", `a\,b\;c`},
+		{`back\slash`, `back\\slash`},
+		{"line1\r\nline2\nline3", `line1\nline2\nline3`},
+	}
+	for _, tt := range tests {
+		if got := EscapeText(tt.in); got != tt.want {
+			t.Errorf("EscapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := UnescapeText(EscapeText(tt.in)); got != strings.ReplaceAll(tt.in, "\r\n", "\n") {
+			t.Errorf("UnescapeText(EscapeText(%q)) = %q", tt.in, got)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Property 是一行 content line，例如 DTSTART;TZID=Asia/Shanghai:20260203T100000
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component 是 BEGIN/END 包裹的组件，例如 VEVENT、VTIMEZONE
+type Component struct {
+	Name       string
+	Properties []Property
+	Children   []*Component
+}
+
+// Get 返回第一个同名属性
+func (c *Component) Get(name string) (Property, bool) {
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// Text 返回反转义后的 TEXT 属性值
+func (c *Component) Text(name string) string {
+	p, ok := c.Get(name)
+	if !ok {
+		return ""
+	}
+	return UnescapeText(p.Value)
+}
+
+// ParsedEvent 是从邀请中解析出的 VEVENT
+type ParsedEvent struct {
+	UID            string
+	Summary        string
+	Description    string
+	Location       string
+	URL            string
+	Organizer      string // 邮箱地址
+	OrganizerName  string
+	Start          time.Time
+	End            time.Time
+	AllDay         bool
+	Status         string
+	RecurrenceRule string
+}
+
+var ErrNoCalendar = errors.New("ical: no VCALENDAR found")
+
+// Parse 解析 iCalendar 文本，返回根 VCALENDAR 组件
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Component
+	var stack []*Component
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", i+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			comp := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, comp)
+			} else if root == nil {
+				root = comp
+			}
+			stack = append(stack, comp)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				continue
+			}
+			cur := stack[len(stack)-1]
+			cur.Properties = append(cur.Properties, prop)
+		}
+	}
+
+	if root == nil || root.Name != "VCALENDAR" {
+		return nil, ErrNoCalendar
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("ical: unterminated %s", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// ParseEvents 解析 iCalendar 文本中的所有 VEVENT，TZID 优先按 IANA 名称加载，
+// 找不到时使用同文件内的 VTIMEZONE 定义，均失败时按 fallback 时区处理
+func ParseEvents(r io.Reader, fallback *time.Location) ([]ParsedEvent, error) {
+	cal, err := Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	if fallback == nil {
+		fallback = time.Local
+	}
+
+	zones := make(map[string]*Component)
+	for _, child := range cal.Children {
+		if child.Name == "VTIMEZONE" {
+			zones[child.Text("TZID")] = child
+		}
+	}
+	resolver := &zoneResolver{zones: zones, fallback: fallback}
+
+	var events []ParsedEvent
+	for _, child := range cal.Children {
+		if child.Name != "VEVENT" {
+			continue
+		}
+		event, err := buildEvent(child, resolver)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func buildEvent(c *Component, resolver *zoneResolver) (ParsedEvent, error) {
+	event := ParsedEvent{
+		UID:            c.Text("UID"),
+		Summary:        c.Text("SUMMARY"),
+		Description:    c.Text("DESCRIPTION"),
+		Location:       c.Text("LOCATION"),
+		URL:            c.Text("URL"),
+		Status:         strings.ToUpper(c.Text("STATUS")),
+		RecurrenceRule: c.Text("RRULE"),
+	}
+
+	if org, ok := c.Get("ORGANIZER"); ok {
+		event.Organizer = strings.TrimPrefix(strings.TrimPrefix(org.Value, "mailto:"), "MAILTO:")
+		event.OrganizerName = strings.Trim(org.Params["CN"], `"`)
+	}
+
+	startProp, ok := c.Get("DTSTART")
+	if !ok {
+		return event, fmt.Errorf("ical: event %q has no DTSTART", event.UID)
+	}
+	start, allDay, err := resolver.parseDateTime(startProp)
+	if err != nil {
+		return event, fmt.Errorf("ical: event %q: DTSTART: %w", event.UID, err)
+	}
+	event.Start = start
+	event.AllDay = allDay
+
+	if endProp, ok := c.Get("DTEND"); ok {
+		end, _, err := resolver.parseDateTime(endProp)
+		if err != nil {
+			return event, fmt.Errorf("ical: event %q: DTEND: %w", event.UID, err)
+		}
+		event.End = end
+	} else if durProp, ok := c.Get("DURATION"); ok {
+		d, err := ParseDuration(durProp.Value)
+		if err != nil {
+			return event, fmt.Errorf("ical: event %q: DURATION: %w", event.UID, err)
+		}
+		event.End = start.Add(d)
+	} else if allDay {
+		event.End = start.AddDate(0, 0, 1)
+	} else {
+		event.End = start
+	}
+
+	return event, nil
+}
+
+// UnescapeText 还原 EscapeText 的转义
+func UnescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// ParseDuration 解析 RFC 5545 DURATION，例如 PT1H30M、P1D、-PT15M
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, ch := range s {
+		switch {
+		case ch == 'T':
+			inTime = true
+		case ch >= '0' && ch <= '9':
+			num += string(ch)
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			num = ""
+			switch {
+			case ch == 'W':
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case ch == 'D':
+				total += time.Duration(n) * 24 * time.Hour
+			case ch == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case ch == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case ch == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	return sign * total, nil
+}
+
+// unfold 读取全部内容行并合并折行（以空格或制表符开头的行）
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseLine 解析 name *(";" param) ":" value，参数值可以带双引号
+func parseLine(line string) (Property, error) {
+	prop := Property{Params: map[string]string{}}
+
+	i := strings.IndexAny(line, ";:")
+	if i < 0 {
+		return prop, fmt.Errorf("missing ':' in %q", line)
+	}
+	prop.Name = strings.ToUpper(line[:i])
+
+	rest := line[i:]
+	for len(rest) > 0 && rest[0] == ';' {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			return prop, fmt.Errorf("invalid parameter in %q", line)
+		}
+		key := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return prop, fmt.Errorf("unterminated quote in %q", line)
+			}
+			val = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return prop, fmt.Errorf("missing ':' in %q", line)
+			}
+			val = rest[:end]
+			rest = rest[end:]
+		}
+		prop.Params[key] = val
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return prop, fmt.Errorf("missing ':' in %q", line)
+	}
+	prop.Value = rest[1:]
+	return prop, nil
+}
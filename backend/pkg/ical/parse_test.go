@@ -0,0 +1,163 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 不在 IANA 数据库中的 TZID，只能依靠 VTIMEZONE 中的规则换算
+const customZones = `BEGIN:VTIMEZONE
+TZID:Custom Eastern
+BEGIN:STANDARD
+DTSTART:19701101T020000
+RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU
+TZOFFSETFROM:-0400
+TZOFFSETTO:-0500
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:19700308T020000
+RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU
+TZOFFSETFROM:-0500
+TZOFFSETTO:-0400
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VTIMEZONE
+TZID:Custom Fixed
+BEGIN:STANDARD
+DTSTART:19700101T000000
+TZOFFSETFROM:+0530
+TZOFFSETTO:+0530
+END:STANDARD
+END:VTIMEZONE
+`
+
+func calendar(body string) string {
+	return strings.ReplaceAll("BEGIN:VCALENDAR\nVERSION:2.0\n"+body+"END:VCALENDAR\n", "\n", "\r\n")
+}
+
+func TestParseEventsTimezones(t *testing.T) {
+	fallback := time.FixedZone("fallback", 9*3600)
+	tests := []struct {
+		name    string
+		dtstart string
+		want    time.Time
+		allDay  bool
+	}{
+		{"utc", "DTSTART:20260203T020000Z", time.Date(2026, 2, 3, 2, 0, 0, 0, time.UTC), false},
+		{"iana tzid", "DTSTART;TZID=Asia/Shanghai:20260203T100000", time.Date(2026, 2, 3, 2, 0, 0, 0, time.UTC), false},
+		{"quoted iana tzid", `DTSTART;TZID="/America/New_York":20260715T100000`, time.Date(2026, 7, 15, 14, 0, 0, 0, time.UTC), false},
+		{"windows tzid", `DTSTART;TZID="China Standard Time":20260203T100000`, time.Date(2026, 2, 3, 2, 0, 0, 0, time.UTC), false},
+		{"vtimezone daylight", "DTSTART;TZID=Custom Eastern:20260715T100000", time.Date(2026, 7, 15, 14, 0, 0, 0, time.UTC), false},
+		{"vtimezone standard", "DTSTART;TZID=Custom Eastern:20260115T100000", time.Date(2026, 1, 15, 15, 0, 0, 0, time.UTC), false},
+		{"vtimezone after dst ends", "DTSTART;TZID=Custom Eastern:20261110T100000", time.Date(2026, 11, 10, 15, 0, 0, 0, time.UTC), false},
+		{"vtimezone without rrule", "DTSTART;TZID=Custom Fixed:20260203T100000", time.Date(2026, 2, 3, 4, 30, 0, 0, time.UTC), false},
+		{"unknown tzid uses fallback", "DTSTART;TZID=Nowhere:20260203T100000", time.Date(2026, 2, 3, 1, 0, 0, 0, time.UTC), false},
+		{"floating time uses fallback", "DTSTART:20260203T100000", time.Date(2026, 2, 3, 1, 0, 0, 0, time.UTC), false},
+		{"all day", "DTSTART;VALUE=DATE:20260203", time.Date(2026, 2, 2, 15, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := calendar(customZones + "BEGIN:VEVENT\nUID:1\n" + tt.dtstart + "\nEND:VEVENT\n")
+			events, err := ParseEvents(strings.NewReader(src), fallback)
+			if err != nil {
+				t.Fatalf("ParseEvents() error: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			e := events[0]
+			if !e.Start.Equal(tt.want) {
+				t.Errorf("Start = %v, want %v", e.Start.UTC(), tt.want)
+			}
+			if e.AllDay != tt.allDay {
+				t.Errorf("AllDay = %v, want %v", e.AllDay, tt.allDay)
+			}
+		})
+	}
+}
+
+func TestParseEventsEnd(t *testing.T) {
+	start := time.Date(2026, 2, 3, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		body string
+		want time.Time
+	}{
+		{"dtend", "DTSTART:20260203T020000Z\nDTEND:20260203T030000Z\n", start.Add(time.Hour)},
+		{"duration", "DTSTART:20260203T020000Z\nDURATION:PT1H30M\n", start.Add(90 * time.Minute)},
+		{"no end", "DTSTART:20260203T020000Z\n", start},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := ParseEvents(strings.NewReader(calendar("BEGIN:VEVENT\n"+tt.body+"END:VEVENT\n")), time.UTC)
+			if err != nil || len(events) != 1 {
+				t.Fatalf("ParseEvents() = %v, %v", events, err)
+			}
+			if !events[0].End.Equal(tt.want) {
+				t.Errorf("End = %v, want %v", events[0].End, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnfoldsLines(t *testing.T) {
+	// 折行可以用空格或制表符开头，也可能切在多字节字符中间
+	summary := "阿里巴巴"
+	src := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART:20260203T020000Z\r\n" +
+		"SUMMARY:" + summary[:4] + "\r\n " + summary[4:8] + "\r\n\t" + summary[8:] + "\r\n" +
+		"ORGANIZER;CN=\"HR; 招聘\":mailto:hr@\r\n example.com\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	events, err := ParseEvents(strings.NewReader(src), time.UTC)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ParseEvents() = %v, %v", events, err)
+	}
+	e := events[0]
+	if e.Summary != summary {
+		t.Errorf("Summary = %q, want %q", e.Summary, summary)
+	}
+	if e.Organizer != "hr@example.com" || e.OrganizerName != "HR; 招聘" {
+		t.Errorf("Organizer = %q (%q)", e.Organizer, e.OrganizerName)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n")); !errors.Is(err, ErrNoCalendar) {
+		t.Errorf("missing VCALENDAR: got %v", err)
+	}
+	if _, err := Parse(strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n")); err == nil {
+		t.Error("unterminated component accepted")
+	}
+	if _, err := Parse(strings.NewReader("BEGIN:VCALENDAR\r\nEND:VEVENT\r\n")); err == nil {
+		t.Error("mismatched END accepted")
+	}
+	if _, err := ParseEvents(strings.NewReader(calendar("BEGIN:VEVENT\nUID:1\nEND:VEVENT\n")), time.UTC); err == nil {
+		t.Error("event without DTSTART accepted")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P1D", 24 * time.Hour, true},
+		{"P1W", 7 * 24 * time.Hour, true},
+		{"P1DT2H", 26 * time.Hour, true},
+		{"-PT15M", -15 * time.Minute, true},
+		{"+PT45S", 45 * time.Second, true},
+		{"1H", 0, false},
+		{"P1H", 0, false},
+		{"PT1", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeLocal = "20060102T150405"
+	dateOnly      = "20060102"
+)
+
+// windowsZones 是 Outlook / Exchange 邀请中常见的 Windows 时区名
+var windowsZones = map[string]string{
+	"China Standard Time":            "Asia/Shanghai",
+	"Taipei Standard Time":           "Asia/Taipei",
+	"Singapore Standard Time":        "Asia/Singapore",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"Korea Standard Time":            "Asia/Seoul",
+	"India Standard Time":            "Asia/Kolkata",
+	"GMT Standard Time":              "Europe/London",
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Romance Standard Time":          "Europe/Paris",
+	"Eastern Standard Time":          "America/New_York",
+	"Central Standard Time":          "America/Chicago",
+	"Mountain Standard Time":         "America/Denver",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+	"UTC":                            "UTC",
+	"Coordinated Universal Time":     "UTC",
+	"(UTC+08:00) Beijing, Chongqing": "Asia/Shanghai",
+}
+
+type zoneResolver struct {
+	zones    map[string]*Component
+	fallback *time.Location
+}
+
+// parseDateTime 解析 DATE / DATE-TIME 属性，返回时间以及是否为全天
+func (z *zoneResolver) parseDateTime(p Property) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.Value)
+	if p.Params["VALUE"] == "DATE" || len(value) == len(dateOnly) {
+		t, err := time.ParseInLocation(dateOnly, value, z.location(p.Params["TZID"]))
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeLocal, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	}
+
+	// 先按 UTC 读出墙上时间，再套用时区
+	wall, err := time.Parse(dateTimeLocal, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	tzid := p.Params["TZID"]
+	if tzid == "" {
+		return inLocation(wall, z.fallback), false, nil
+	}
+	if loc := loadLocation(tzid); loc != nil {
+		return inLocation(wall, loc), false, nil
+	}
+	if vtz, ok := z.zones[tzid]; ok {
+		offset, err := vtimezoneOffset(vtz, wall)
+		if err == nil {
+			return inLocation(wall, time.FixedZone(tzid, offset)), false, nil
+		}
+	}
+	return inLocation(wall, z.fallback), false, nil
+}
+
+func (z *zoneResolver) location(tzid string) *time.Location {
+	if tzid != "" {
+		if loc := loadLocation(tzid); loc != nil {
+			return loc
+		}
+	}
+	return z.fallback
+}
+
+func loadLocation(tzid string) *time.Location {
+	name := strings.TrimPrefix(strings.Trim(tzid, `"`), "/")
+	if mapped, ok := windowsZones[name]; ok {
+		name = mapped
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+func inLocation(wall time.Time, loc *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(),
+		wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+}
+
+// vtimezoneOffset 根据 VTIMEZONE 的 STANDARD / DAYLIGHT 规则求 wall 时刻的 UTC 偏移（秒）。
+// 支持 Outlook 常见的 FREQ=YEARLY;BYMONTH=m;BYDAY=nDD 规则，取在 wall 之前最近生效的一条
+func vtimezoneOffset(vtz *Component, wall time.Time) (int, error) {
+	var (
+		best      time.Time
+		bestFound bool
+		offset    int
+	)
+
+	for _, obs := range vtz.Children {
+		if obs.Name != "STANDARD" && obs.Name != "DAYLIGHT" {
+			continue
+		}
+		to, ok := obs.Get("TZOFFSETTO")
+		if !ok {
+			continue
+		}
+		off, err := parseOffset(to.Value)
+		if err != nil {
+			return 0, err
+		}
+		startProp, ok := obs.Get("DTSTART")
+		if !ok {
+			continue
+		}
+		dtstart, err := time.Parse(dateTimeLocal, startProp.Value)
+		if err != nil {
+			return 0, err
+		}
+
+		onset, ok := latestOnset(obs, dtstart, wall)
+		if !ok {
+			continue
+		}
+		if !bestFound || onset.After(best) {
+			best, bestFound, offset = onset, true, off
+		}
+	}
+
+	if !bestFound {
+		return 0, fmt.Errorf("no applicable observance in VTIMEZONE")
+	}
+	return offset, nil
+}
+
+// latestOnset 返回 wall 之前（含）该规则最近一次生效的墙上时间
+func latestOnset(obs *Component, dtstart, wall time.Time) (time.Time, bool) {
+	if dtstart.After(wall) {
+		return time.Time{}, false
+	}
+
+	rrule := obs.Text("RRULE")
+	if rrule == "" {
+		return dtstart, true
+	}
+
+	rule := parseRule(rrule)
+	if rule["FREQ"] != "YEARLY" {
+		return dtstart, true
+	}
+	month, err := strconv.Atoi(rule["BYMONTH"])
+	if err != nil || month < 1 || month > 12 {
+		return dtstart, true
+	}
+
+	for year := wall.Year(); year >= wall.Year()-1; year-- {
+		day := dtstart.Day()
+		if byDay := rule["BYDAY"]; byDay != "" {
+			d, ok := nthWeekday(year, time.Month(month), byDay)
+			if !ok {
+				return dtstart, true
+			}
+			day = d
+		}
+		onset := time.Date(year, time.Month(month), day,
+			dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, time.UTC)
+		if !onset.After(wall) && !onset.Before(dtstart) {
+			return onset, true
+		}
+	}
+	return dtstart, true
+}
+
+func parseRule(s string) map[string]string {
+	rule := make(map[string]string)
+	for _, part := range strings.Split(s, ";") {
+		if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+			rule[strings.ToUpper(kv[0])] = strings.ToUpper(kv[1])
+		}
+	}
+	return rule
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// nthWeekday 解析 BYDAY，例如 2SU 表示第二个周日，-1SU 表示最后一个周日
+func nthWeekday(year int, month time.Month, byDay string) (int, bool) {
+	if len(byDay) < 2 {
+		return 0, false
+	}
+	wd, ok := weekdays[byDay[len(byDay)-2:]]
+	if !ok {
+		return 0, false
+	}
+	n := 1
+	if prefix := byDay[:len(byDay)-2]; prefix != "" {
+		v, err := strconv.Atoi(prefix)
+		if err != nil || v == 0 {
+			return 0, false
+		}
+		n = v
+	}
+
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		day := 1 + (int(wd)-int(first.Weekday())+7)%7 + (n-1)*7
+		if day > daysIn(year, month) {
+			return 0, false
+		}
+		return day, true
+	}
+
+	lastDay := daysIn(year, month)
+	last := time.Date(year, month, lastDay, 0, 0, 0, 0, time.UTC)
+	day := lastDay - (int(last.Weekday())-int(wd)+7)%7 + (n+1)*7
+	if day < 1 {
+		return 0, false
+	}
+	return day, true
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// parseOffset 解析 UTC 偏移，例如 +0800、-0530、+013000
+func parseOffset(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 5 && len(s) != 7 {
+		return 0, fmt.Errorf("invalid utc offset %q", s)
+	}
+	sign := 1
+	switch s[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("invalid utc offset %q", s)
+	}
+	h, err1 := strconv.Atoi(s[1:3])
+	m, err2 := strconv.Atoi(s[3:5])
+	sec := 0
+	var err3 error
+	if len(s) == 7 {
+		sec, err3 = strconv.Atoi(s[5:7])
+	}
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid utc offset %q", s)
+	}
+	return sign * (h*3600 + m*60 + sec), nil
+}
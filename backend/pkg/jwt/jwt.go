@@ -1,42 +1,234 @@
 package jwt
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"offermatrix/internal/config"
 )
 
+// 早期版本内置的 HS256 密钥和示例配置中的占位密钥，只允许在开发模式下使用
+const (
+	DefaultSecret       = "offermatrix-secret-key"
+	exampleConfigSecret = "your-jwt-secret-key-here"
+)
+
+// 配置缺省时的密钥目录和轮换周期
+const (
+	defaultKeysDir      = "keys"
+	defaultRotationDays = 30
+	rotationCheckPeriod = time.Hour
+	keyRetentionSlack   = time.Minute
+)
+
+// keySet 为 nil 表示使用 HS256 共享密钥
+var keySet *KeySet
+
+// 配置缺省时使用的有效期
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// ChallengeTTL 两步验证 challenge token 的有效期
+const ChallengeTTL = 5 * time.Minute
+
+// ImpersonationTTL 管理员只读代登录 token 的有效期，到期后需要重新发起
+const ImpersonationTTL = 30 * time.Minute
+
+// challenge token 的用途标记，带 purpose 的 token 不能当作 access token 使用
+const purposeTwoFactor = "2fa"
+
 type Claims struct {
-	UserID   int64  `json:"user_id"`
-	Username string `json:"username"`
+	UserID    int64  `json:"user_id"`
+	Username  string `json:"username"`
+	SessionID int64  `json:"sid"`
+	Purpose   string `json:"purpose,omitempty"`
+	// ImpersonatorID 非 0 表示这是管理员以只读方式代登录该用户时签发的 token
+	ImpersonatorID int64 `json:"imp,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成 JWT token
-func GenerateToken(userID int64, username string) (string, error) {
+// AccessTTL 返回 access token 有效期
+func AccessTTL() time.Duration {
+	if m := config.AppConfig.JWT.AccessTokenMinutes; m > 0 {
+		return time.Duration(m) * time.Minute
+	}
+	return defaultAccessTTL
+}
+
+// RefreshTTL 返回 refresh token 有效期
+func RefreshTTL() time.Duration {
+	if d := config.AppConfig.JWT.RefreshTokenDays; d > 0 {
+		return time.Duration(d) * 24 * time.Hour
+	}
+	return defaultRefreshTTL
+}
+
+// Init 根据配置准备签名密钥，服务启动时调用一次。
+// RS256 / EdDSA 使用密钥目录中的密钥并按周期轮换；HS256 使用共享密钥，
+// 非开发模式下拒绝空密钥和内置的默认密钥
+func Init() error {
 	cfg := config.AppConfig.JWT
+	switch alg := cfg.Algorithm; alg {
+	case AlgHS256:
+		devMode := config.AppConfig.Server.Mode == "dev"
+		if !devMode && (cfg.Secret == "" || cfg.Secret == DefaultSecret || cfg.Secret == exampleConfigSecret) {
+			return errors.New("jwt.secret is empty or a well-known default; set a strong secret, " +
+				"switch jwt.algorithm to RS256/EdDSA, or set server.mode to dev")
+		}
+		keySet = nil
+		return nil
+	case "", AlgRS256, AlgEdDSA:
+		if alg == "" {
+			alg = AlgEdDSA
+		}
+		dir := cfg.KeysDir
+		if dir == "" {
+			dir = defaultKeysDir
+		}
+		days := cfg.RotationDays
+		if days <= 0 {
+			days = defaultRotationDays
+		}
+		ks, err := NewKeySet(dir, alg, time.Duration(days)*24*time.Hour, maxTokenTTL()+keyRetentionSlack)
+		if err != nil {
+			return err
+		}
+		keySet = ks
+		return nil
+	default:
+		return fmt.Errorf("unsupported jwt.algorithm %q", alg)
+	}
+}
+
+// maxTokenTTL 返回各类 token 中最长的有效期，被替换的密钥要保留到它签发的 token 全部过期
+func maxTokenTTL() time.Duration {
+	ttl := AccessTTL()
+	for _, d := range []time.Duration{ChallengeTTL, ImpersonationTTL} {
+		if d > ttl {
+			ttl = d
+		}
+	}
+	return ttl
+}
+
+// RunRotation 定时检查签名密钥是否到期轮换，HS256 模式下直接返回
+func RunRotation(ctx context.Context) {
+	if keySet == nil {
+		return
+	}
+
+	ticker := time.NewTicker(rotationCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := keySet.Rotate(now); err != nil {
+				log.Printf("Failed to rotate jwt signing key: %v", err)
+			}
+		}
+	}
+}
+
+// GenerateToken 生成绑定到登录会话的短期 access token
+func GenerateToken(userID int64, username string, sessionID int64) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:    userID,
+		Username:  username,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return sign(claims)
+}
+
+// GenerateImpersonationToken 为管理员只读代登录签发 access token，不配发 refresh token，到期后需重新发起
+func GenerateImpersonationToken(userID int64, username string, sessionID, adminID int64) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:         userID,
+		Username:       username,
+		SessionID:      sessionID,
+		ImpersonatorID: adminID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(ImpersonationTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return sign(claims)
+}
+
+// GenerateChallengeToken 生成密码验证通过、等待两步验证的短期 token
+func GenerateChallengeToken(userID int64, username string) (string, error) {
+	now := time.Now()
 	claims := Claims{
 		UserID:   userID,
 		Username: username,
+		Purpose:  purposeTwoFactor,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.ExpireHour) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.Secret))
+	return sign(claims)
 }
 
-// ParseToken 解析 JWT token
+// ParseToken 解析 access token，拒绝 challenge token
 func ParseToken(tokenString string) (*Claims, error) {
-	cfg := config.AppConfig.JWT
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.Secret), nil
-	})
+	claims, err := parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// ParseChallengeToken 解析两步验证 challenge token
+func ParseChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purposeTwoFactor {
+		return nil, errors.New("invalid challenge token")
+	}
+	return claims, nil
+}
+
+func sign(claims Claims) (string, error) {
+	if keySet == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(config.AppConfig.JWT.Secret))
+	}
+
+	key := keySet.Signing()
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
 
+func parse(tokenString string) (*Claims, error) {
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if keySet != nil {
+		methods = []string{AlgRS256, AlgEdDSA}
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey, jwt.WithValidMethods(methods))
 	if err != nil {
 		return nil, err
 	}
@@ -47,3 +239,20 @@ func ParseToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// verificationKey 按 token 头部的 kid 选择公钥，并要求算法与密钥一致
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	if keySet == nil {
+		return []byte(config.AppConfig.JWT.Secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := keySet.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Alg {
+		return nil, errors.New("signing algorithm does not match key")
+	}
+	return key.Public(), nil
+}
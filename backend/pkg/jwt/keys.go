@@ -0,0 +1,287 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	rsaKeyBits       = 2048
+	createdAtHeader  = "Created-At"
+	algorithmHeader  = "Algorithm"
+	keyFileExtension = ".pem"
+	// 未知 kid 触发重新加载密钥目录的最小间隔，用于多实例共享目录时发现其他实例轮换出的新密钥
+	reloadInterval = 10 * time.Second
+)
+
+// Key 是一把签名密钥，私钥以 PKCS#8 PEM 保存在密钥目录，文件名即 kid
+type Key struct {
+	ID        string
+	Alg       string
+	CreatedAt time.Time
+	private   crypto.Signer
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Alg == AlgRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+func (k *Key) Public() crypto.PublicKey {
+	return k.private.Public()
+}
+
+// KeySet 管理密钥目录中的全部密钥：最新的一把用于签名，其余在其签发的 token 全部过期前继续用于验签
+type KeySet struct {
+	dir      string
+	alg      string
+	rotation time.Duration
+	// retention 是被替换的密钥继续保留的时长，应不短于最长的 token 有效期
+	retention time.Duration
+
+	mu         sync.RWMutex
+	keys       map[string]*Key
+	signing    *Key
+	lastReload time.Time
+}
+
+func NewKeySet(dir, alg string, rotation, retention time.Duration) (*KeySet, error) {
+	if alg != AlgRS256 && alg != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported key algorithm %q", alg)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ks := &KeySet{
+		dir:       dir,
+		alg:       alg,
+		rotation:  rotation,
+		retention: retention,
+		keys:      make(map[string]*Key),
+	}
+	if err := ks.Rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Signing 返回当前签名密钥
+func (ks *KeySet) Signing() *Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.signing
+}
+
+// Lookup 按 kid 查找验签密钥，找不到时重新加载一次目录
+func (ks *KeySet) Lookup(kid string) (*Key, bool) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	stale := time.Since(ks.lastReload) > reloadInterval
+	ks.mu.RUnlock()
+	if ok || !stale {
+		return key, ok
+	}
+
+	if err := ks.reload(); err != nil {
+		return nil, false
+	}
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok = ks.keys[kid]
+	return key, ok
+}
+
+// Keys 返回全部仍有效的密钥，按创建时间升序
+func (ks *KeySet) Keys() []*Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	keys := make([]*Key, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys
+}
+
+// Rotate 重新加载目录；最新的同算法密钥超过轮换周期（或不存在）时生成新密钥，
+// 并删除被替换超过 retention 的旧密钥。可以重复调用，由定时任务驱动
+func (ks *KeySet) Rotate(now time.Time) error {
+	if err := ks.reload(); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.signing == nil || now.Sub(ks.signing.CreatedAt) >= ks.rotation {
+		key, err := ks.generate(now)
+		if err != nil {
+			return err
+		}
+		ks.keys[key.ID] = key
+		ks.signing = key
+	}
+
+	ks.prune(now)
+	return nil
+}
+
+// reload 从目录加载密钥，并选出最新的同算法密钥作为签名密钥
+func (ks *KeySet) reload() error {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*Key)
+	var signing *Key
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyFileExtension) {
+			continue
+		}
+		key, err := readKey(filepath.Join(ks.dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("load signing key %s: %w", entry.Name(), err)
+		}
+		keys[key.ID] = key
+		if key.Alg == ks.alg && (signing == nil || key.CreatedAt.After(signing.CreatedAt)) {
+			signing = key
+		}
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.signing = signing
+	ks.lastReload = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+// prune 删除已被更新密钥替换超过 retention 的密钥，调用方需持有写锁
+func (ks *KeySet) prune(now time.Time) {
+	keys := make([]*Key, 0, len(ks.keys))
+	for _, k := range ks.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+
+	for i := 0; i < len(keys)-1; i++ {
+		key := keys[i]
+		if key == ks.signing {
+			continue
+		}
+		supersededAt := keys[i+1].CreatedAt
+		if now.Sub(supersededAt) <= ks.retention {
+			continue
+		}
+		if err := os.Remove(filepath.Join(ks.dir, key.ID+keyFileExtension)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		delete(ks.keys, key.ID)
+	}
+}
+
+// generate 生成新密钥并写入目录，调用方需持有写锁
+func (ks *KeySet) generate(now time.Time) (*Key, error) {
+	var private crypto.Signer
+	var err error
+	switch ks.alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := &Key{
+		ID:        now.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix),
+		Alg:       ks.alg,
+		CreatedAt: now,
+		private:   private,
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	block := &pem.Block{
+		Type: "PRIVATE KEY",
+		Headers: map[string]string{
+			algorithmHeader: key.Alg,
+			createdAtHeader: now.UTC().Format(time.RFC3339),
+		},
+		Bytes: der,
+	}
+
+	// 先写临时文件再改名，避免其他实例读到写了一半的密钥
+	path := filepath.Join(ks.dir, key.ID+keyFileExtension)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, pem.EncodeToMemory(block), 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func readKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{ID: strings.TrimSuffix(filepath.Base(path), keyFileExtension)}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Alg, key.private = AlgRS256, private
+	case ed25519.PrivateKey:
+		key.Alg, key.private = AlgEdDSA, private
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+
+	key.CreatedAt, err = time.Parse(time.RFC3339, block.Headers[createdAtHeader])
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid %s header", createdAtHeader)
+	}
+	return key, nil
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const anthropicVersion = "2023-06-01"
+
+// Anthropic 调用 Claude Messages API
+type Anthropic struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	client  *http.Client
+}
+
+type anthropicRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature float64   `json:"temperature"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func (p *Anthropic) Complete(ctx context.Context, req Request) (string, error) {
+	resp, err := p.do(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("llm: decode response: %w", err)
+	}
+	var b strings.Builder
+	for _, block := range out.Content {
+		if block.Type == "text" {
+			b.WriteString(block.Text)
+		}
+	}
+	return b.String(), nil
+}
+
+func (p *Anthropic) do(ctx context.Context, req Request, stream bool) (*http.Response, error) {
+	// Messages API 的 system 是独立字段
+	var system []string
+	var messages []Message
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		messages = append(messages, m)
+	}
+
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 1024
+	}
+
+	body, err := json.Marshal(anthropicRequest{
+		Model:       p.Model,
+		System:      strings.Join(system, "\n\n"),
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.APIKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return nil, fmt.Errorf("llm: API 调用失败: %d %s", resp.StatusCode, msg)
+	}
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Fake 是确定性的离线 Provider：相同输入总是得到相同输出，用于本地开发和测试。
+// Responses 按 Task 指定固定回复；未指定时使用内置的规则提取
+type Fake struct {
+	Responses map[string]string
+}
+
+func NewFake() *Fake {
+	return &Fake{Responses: map[string]string{}}
+}
+
+var (
+	fakeURLPattern      = regexp.MustCompile(`https?://[^\s，。,"']+`)
+	fakeDateTimePattern = regexp.MustCompile(`(\d{4})-(\d{1,2})-(\d{1,2})[ T](\d{1,2}):(\d{2})`)
+	fakeRounds          = []string{"技术一面", "技术二面", "技术三面", "业务面", "HR面", "终面", "笔试", "AI面"}
+	fakeSkills          = []string{"Go", "Java", "Python", "C++", "TypeScript", "React", "Vue", "MySQL", "Redis", "Kafka", "Kubernetes", "Docker", "分布式", "微服务", "机器学习"}
+)
+
+func (f *Fake) Complete(ctx context.Context, req Request) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if resp, ok := f.Responses[req.Task]; ok {
+		return resp, nil
+	}
+
+	input := lastUserMessage(req.Messages)
+	switch req.Task {
+	case TaskParseInvitation:
+		return fakeParseInvitation(input), nil
+	case TaskAnalyzeJD:
+		return fakeAnalyzeJD(input), nil
+	case TaskAnalyzeJDStream:
+		return fakeAnalyzeJDMarkdown(input), nil
+	default:
+		return "OK", nil
+	}
+}
+
+func lastUserMessage(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+func fakeParseInvitation(input string) string {
+	result := map[string]interface{}{
+		"company_name": nil,
+		"round_name":   nil,
+		"start_time":   nil,
+		"end_time":     nil,
+		"meeting_link": nil,
+		"confidence":   0.5,
+	}
+
+	// 只看用户原文，忽略提示词中的示例
+	if i := strings.LastIndex(input, "## 用户输入"); i >= 0 {
+		input = input[i:]
+	}
+
+	if link := fakeURLPattern.FindString(input); link != "" {
+		result["meeting_link"] = link
+	}
+	if m := fakeDateTimePattern.FindStringSubmatch(input); m != nil {
+		if start, err := time.Parse("2006-1-2 15:04", m[1]+"-"+m[2]+"-"+m[3]+" "+m[4]+":"+m[5]); err == nil {
+			result["start_time"] = start.Format("2006-01-02T15:04:05")
+			result["end_time"] = start.Add(time.Hour).Format("2006-01-02T15:04:05")
+		}
+	}
+	for _, round := range fakeRounds {
+		if strings.Contains(input, round) {
+			result["round_name"] = round
+			break
+		}
+	}
+	if start := strings.Index(input, "【"); start >= 0 {
+		if end := strings.Index(input[start:], "】"); end > 0 {
+			result["company_name"] = input[start+len("【") : start+end]
+		}
+	}
+
+	out, _ := json.Marshal(result)
+	return string(out)
+}
+
+func fakeAnalyzeJD(input string) string {
+	skills := []string{}
+	for _, skill := range fakeSkills {
+		if strings.Contains(input, skill) {
+			skills = append(skills, skill)
+		}
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{
+		"responsibilities": []string{"根据 JD 完成岗位相关的研发工作"},
+		"skills":           skills,
+		"advantages":       []string{"（离线模式）未连接大模型，无法评估岗位优势"},
+		"concerns":         []string{"（离线模式）未连接大模型，无法评估潜在顾虑"},
+		"salary_analysis":  "（离线模式）未连接大模型，无法进行薪资分析",
+	})
+	return string(out)
+}
+
+func fakeAnalyzeJDMarkdown(input string) string {
+	var b strings.Builder
+	b.WriteString("### 技术栈与技能要求\n\n")
+	found := false
+	for _, skill := range fakeSkills {
+		if strings.Contains(input, skill) {
+			b.WriteString("- " + skill + "\n")
+			found = true
+		}
+	}
+	if !found {
+		b.WriteString("- 未识别到明确的技术栈\n")
+	}
+	b.WriteString("\n### 说明\n\n（离线模式）未连接大模型，仅提取了 JD 中出现的技术关键词。")
+	return b.String()
+}
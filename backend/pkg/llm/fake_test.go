@@ -0,0 +1,86 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"offermatrix/internal/config"
+)
+
+func TestNewFake(t *testing.T) {
+	p, err := New(config.LLMConfig{Provider: "fake"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, ok := p.(*Fake); !ok {
+		t.Fatalf("New() = %T, want *Fake", p)
+	}
+	if _, err := New(config.LLMConfig{}); err != ErrNotConfigured {
+		t.Errorf("empty provider: got %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestFakeParseInvitation(t *testing.T) {
+	req := Request{
+		Task:     TaskParseInvitation,
+		Messages: []Message{{Role: "user", Content: "示例：【示例公司】终面\n## 用户输入\n【腾讯】HR面 2026-4-2 9:05 https://zoom.us/j/42"}},
+	}
+	out, err := NewFake().Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("fake output is not JSON: %v\n%s", err, out)
+	}
+	want := map[string]interface{}{
+		"company_name": "腾讯",
+		"round_name":   "HR面",
+		"start_time":   "2026-04-02T09:05:00",
+		"end_time":     "2026-04-02T10:05:00",
+		"meeting_link": "https://zoom.us/j/42",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestFakeAnalyzeJD(t *testing.T) {
+	req := Request{Task: TaskAnalyzeJD, Messages: []Message{{Role: "user", Content: "精通 Java 与 Kafka"}}}
+	first, err := NewFake().Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	second, _ := NewFake().Complete(context.Background(), req)
+	if first != second {
+		t.Errorf("fake output is not deterministic")
+	}
+
+	var got struct {
+		Skills []string `json:"skills"`
+	}
+	if err := json.Unmarshal([]byte(first), &got); err != nil {
+		t.Fatalf("fake output is not JSON: %v", err)
+	}
+	if len(got.Skills) != 2 || got.Skills[0] != "Java" || got.Skills[1] != "Kafka" {
+		t.Errorf("skills = %v, want [Java Kafka]", got.Skills)
+	}
+}
+
+func TestFakeStreamReplaysCompleteText(t *testing.T) {
+	f := NewFake()
+	f.Responses[TaskAnalyzeJDStream] = "这是一段超过十六个字符的固定回复，用于检查分片回放"
+
+	var chunks []string
+	text, err := Stream(context.Background(), f, Request{Task: TaskAnalyzeJDStream}, func(d string) error {
+		chunks = append(chunks, d)
+		return nil
+	})
+	if err != nil || text != f.Responses[TaskAnalyzeJDStream] || len(chunks) < 2 {
+		t.Fatalf("Stream() = %q, %v, %d chunks", text, err, len(chunks))
+	}
+}
@@ -0,0 +1,108 @@
+// Package llm 封装服务端调用的大模型提供商
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"offermatrix/internal/config"
+)
+
+// ErrNotConfigured 表示未配置大模型
+var ErrNotConfigured = errors.New("llm: provider not configured")
+
+type Message struct {
+	Role    string `json:"role"` // system / user / assistant
+	Content string `json:"content"`
+}
+
+// 调用场景
+const (
+	TaskParseInvitation = "parse_invitation"
+	TaskAnalyzeJD       = "analyze_jd"
+	TaskAnalyzeJDStream = "analyze_jd_stream"
+)
+
+type Request struct {
+	// Task 标识调用场景，用于日志，以及 Fake provider 选择固定回复
+	Task        string
+	Messages    []Message
+	MaxTokens   int
+	Temperature float64
+}
+
+// Provider 是一个可以完成对话补全的大模型
+type Provider interface {
+	Complete(ctx context.Context, req Request) (string, error)
+}
+
+var defaultModels = map[string]string{
+	"openai":    "gpt-4o-mini",
+	"qwen":      "qwen-turbo",
+	"zhipu":     "glm-4-flash",
+	"anthropic": "claude-3-haiku-20240307",
+}
+
+var defaultBaseURLs = map[string]string{
+	"openai":    "https://api.openai.com/v1",
+	"qwen":      "https://dashscope.aliyuncs.com/compatible-mode/v1",
+	"zhipu":     "https://open.bigmodel.cn/api/paas/v4",
+	"anthropic": "https://api.anthropic.com",
+}
+
+// New 根据配置创建 Provider
+func New(cfg config.LLMConfig) (Provider, error) {
+	provider := strings.ToLower(cfg.Provider)
+	if provider == "claude" {
+		provider = "anthropic"
+	}
+	if provider == "" {
+		return nil, ErrNotConfigured
+	}
+	if provider == "fake" {
+		return NewFake(), nil
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("llm: api_key is required for provider %s", provider)
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultModels[provider]
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURLs[provider]
+	}
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 120 * time.Second
+	}
+	// 超时只限制等待响应头的时间，流式输出本身可能持续数分钟
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+	client := &http.Client{Transport: transport}
+
+	switch provider {
+	case "openai", "qwen", "zhipu":
+		return &OpenAICompatible{BaseURL: strings.TrimRight(baseURL, "/"), APIKey: cfg.APIKey, Model: model, client: client}, nil
+	case "anthropic":
+		return &Anthropic{BaseURL: strings.TrimRight(baseURL, "/"), APIKey: cfg.APIKey, Model: model, client: client}, nil
+	default:
+		return nil, fmt.Errorf("llm: unknown provider %q", cfg.Provider)
+	}
+}
+
+// ExtractJSON 从模型输出中截取第一个 { 到最后一个 } 之间的内容，兼容被包在代码块中的 JSON
+func ExtractJSON(s string) (string, error) {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end < start {
+		return "", errors.New("llm: no JSON object in response")
+	}
+	return s[start : end+1], nil
+}
@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OpenAICompatible 调用 /chat/completions 接口，适用于 OpenAI、通义千问、智谱等兼容服务
+type OpenAICompatible struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	client  *http.Client
+}
+
+type chatRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	Temperature float64   `json:"temperature"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+func (p *OpenAICompatible) Complete(ctx context.Context, req Request) (string, error) {
+	resp, err := p.do(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("llm: decode response: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("llm: empty response")
+	}
+	return out.Choices[0].Message.Content, nil
+}
+
+func (p *OpenAICompatible) do(ctx context.Context, req Request, stream bool) (*http.Response, error) {
+	body, err := json.Marshal(chatRequest{
+		Model:       p.Model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.APIKey)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return nil, fmt.Errorf("llm: API 调用失败: %d %s", resp.StatusCode, msg)
+	}
+	return resp, nil
+}
@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Streamer 是支持流式输出的 Provider，onDelta 每收到一段增量文本调用一次，返回错误会中止流
+type Streamer interface {
+	Stream(ctx context.Context, req Request, onDelta func(delta string) error) (string, error)
+}
+
+// Stream 优先使用流式接口，Provider 不支持时退化为一次性返回完整结果
+func Stream(ctx context.Context, p Provider, req Request, onDelta func(delta string) error) (string, error) {
+	if s, ok := p.(Streamer); ok {
+		return s.Stream(ctx, req, onDelta)
+	}
+	text, err := p.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if err := onDelta(text); err != nil {
+		return "", err
+	}
+	return text, nil
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (p *OpenAICompatible) Stream(ctx context.Context, req Request, onDelta func(string) error) (string, error) {
+	resp, err := p.do(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	err = readSSE(resp.Body, func(_, data string) (bool, error) {
+		if data == "[DONE]" {
+			return true, nil
+		}
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, err
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			full.WriteString(choice.Delta.Content)
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return full.String(), ctx.Err()
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *Anthropic) Stream(ctx context.Context, req Request, onDelta func(string) error) (string, error) {
+	resp, err := p.do(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	err = readSSE(resp.Body, func(_, data string) (bool, error) {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return false, err
+		}
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta.Type != "text_delta" || event.Delta.Text == "" {
+				return false, nil
+			}
+			full.WriteString(event.Delta.Text)
+			return false, onDelta(event.Delta.Text)
+		case "message_stop":
+			return true, nil
+		case "error":
+			return false, &streamError{message: event.Error.Message}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return full.String(), ctx.Err()
+}
+
+// Stream 把完整回复按固定大小切片回放，便于离线测试流式链路
+func (f *Fake) Stream(ctx context.Context, req Request, onDelta func(string) error) (string, error) {
+	text, err := f.Complete(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	runes := []rune(text)
+	const chunk = 16
+	for i := 0; i < len(runes); i += chunk {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		end := i + chunk
+		if end > len(runes) {
+			end = len(runes)
+		}
+		if err := onDelta(string(runes[i:end])); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+type streamError struct {
+	message string
+}
+
+func (e *streamError) Error() string {
+	return "llm: stream error: " + e.message
+}
+
+// readSSE 逐条解析 text/event-stream，handle 返回 true 表示收到了结束事件。
+// 没有收到结束事件就断开时返回 io.ErrUnexpectedEOF，调用方应丢弃已收到的部分结果
+func readSSE(r io.Reader, handle func(event, data string) (bool, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				done, err := handle(event, strings.Join(data, "\n"))
+				if err != nil || done {
+					return err
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// 注释行（心跳）
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		done, err := handle(event, strings.Join(data, "\n"))
+		if err != nil || done {
+			return err
+		}
+	}
+	return io.ErrUnexpectedEOF
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sseServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, body)
+	}))
+}
+
+func TestReadSSERequiresTerminalEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{"done", "data: a\n\ndata: [DONE]\n\n", nil},
+		{"done without trailing blank line", "data: a\n\ndata: [DONE]", nil},
+		{"truncated", "data: a\n\ndata: b\n\n", io.ErrUnexpectedEOF},
+		{"empty", "", io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := readSSE(strings.NewReader(tt.body), func(_, data string) (bool, error) {
+				return data == "[DONE]", nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("readSSE() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenAIStream(t *testing.T) {
+	chunk := func(s string) string {
+		return `data: {"choices":[{"delta":{"content":"` + s + `"}}]}` + "\n\n"
+	}
+
+	srv := sseServer(chunk("你好") + chunk("，世界") + "data: [DONE]\n\n")
+	defer srv.Close()
+	p := &OpenAICompatible{BaseURL: srv.URL, Model: "m", client: srv.Client()}
+
+	var deltas []string
+	text, err := p.Stream(context.Background(), Request{}, func(d string) error {
+		deltas = append(deltas, d)
+		return nil
+	})
+	if err != nil || text != "你好，世界" || len(deltas) != 2 {
+		t.Fatalf("Stream() = %q, %v, deltas %v", text, err, deltas)
+	}
+
+	truncated := sseServer(chunk("你好"))
+	defer truncated.Close()
+	p = &OpenAICompatible{BaseURL: truncated.URL, Model: "m", client: truncated.Client()}
+	if _, err := p.Stream(context.Background(), Request{}, func(string) error { return nil }); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("truncated stream: got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestAnthropicStream(t *testing.T) {
+	delta := func(s string) string {
+		return "event: content_block_delta\n" +
+			`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"` + s + `"}}` + "\n\n"
+	}
+	stop := "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"
+
+	srv := sseServer(delta("a") + delta("b") + stop)
+	defer srv.Close()
+	p := &Anthropic{BaseURL: srv.URL, Model: "m", client: srv.Client()}
+	text, err := p.Stream(context.Background(), Request{}, func(string) error { return nil })
+	if err != nil || text != "ab" {
+		t.Fatalf("Stream() = %q, %v", text, err)
+	}
+
+	truncated := sseServer(delta("a"))
+	defer truncated.Close()
+	p = &Anthropic{BaseURL: truncated.URL, Model: "m", client: truncated.Client()}
+	if _, err := p.Stream(context.Background(), Request{}, func(string) error { return nil }); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("truncated stream: got %v, want io.ErrUnexpectedEOF", err)
+	}
+}
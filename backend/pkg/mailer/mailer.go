@@ -0,0 +1,79 @@
+// Package mailer 提供可替换的邮件发送实现
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Sender 发送一封纯文本邮件
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPConfig SMTP 服务器配置
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// New 根据配置创建 Sender，未配置 SMTP 主机时退化为 LogSender
+func New(cfg SMTPConfig) Sender {
+	if cfg.Host == "" {
+		return LogSender{}
+	}
+	return NewSMTPSender(cfg)
+}
+
+// SMTPSender 通过 SMTP（PLAIN 认证，服务器支持时使用 STARTTLS）发送邮件
+type SMTPSender struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender {
+	if cfg.Port == "" {
+		cfg.Port = "25"
+	}
+	return &SMTPSender{cfg: cfg}
+}
+
+func (s *SMTPSender) Send(to, subject, body string) error {
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	return smtp.SendMail(addr, auth, s.cfg.From, []string{to}, buildMessage(s.cfg.From, to, subject, body))
+}
+
+// LogSender 只把邮件打印到日志，用于本地开发或未配置 SMTP 的情况
+type LogSender struct{}
+
+func (LogSender) Send(to, subject, body string) error {
+	log.Printf("[mailer] to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
+
+func buildMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
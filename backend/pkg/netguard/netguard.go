@@ -0,0 +1,105 @@
+// Package netguard 限制发往用户提供地址的出站请求只能访问公网，防止借回调探测内网或云厂商元数据服务
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress 目标地址不是公网地址
+var ErrForbiddenAddress = errors.New("address is not publicly routable")
+
+// blockedNets 是 net.IP 自带判断之外需要拒绝的网段
+var blockedNets = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级 NAT，部分云厂商的元数据服务也在此网段
+	"192.0.0.0/24",  // IETF 协议分配
+	"198.18.0.0/15", // 基准测试
+	"64:ff9b::/96",  // NAT64，可映射到任意 IPv4 内网地址
+)
+
+// IsPublic 判断 ip 是否可以作为出站请求的目标
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Control 用作 net.Dialer.Control，在建立连接前检查实际连接的 IP。
+// 检查发生在域名解析之后，DNS 重绑定和重定向都无法绕过
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
+
+// NewClient 返回只能连接公网地址的 HTTP 客户端。不读取环境变量中的代理，否则检查的只是代理地址
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// CheckHost 解析主机名，要求所有地址都是公网地址。用于保存地址时尽早报错，
+// 真正的防护在 Control 中，解析结果之后变化也会被拦截
+func CheckHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublic(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
@@ -0,0 +1,68 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.100.100.200", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublic(net.ParseIP(tt.ip)); got != tt.public {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
+
+func TestControl(t *testing.T) {
+	if err := Control("tcp", "8.8.8.8:443", nil); err != nil {
+		t.Errorf("public address rejected: %v", err)
+	}
+	for _, addr := range []string{"127.0.0.1:80", "[::1]:443", "169.254.169.254:80"} {
+		if err := Control("tcp", addr, nil); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Control(%s) = %v, want ErrForbiddenAddress", addr, err)
+		}
+	}
+}
+
+func TestCheckHostLiteral(t *testing.T) {
+	if err := CheckHost(context.Background(), "192.168.0.10"); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("private literal accepted: %v", err)
+	}
+	if err := CheckHost(context.Background(), "1.1.1.1"); err != nil {
+		t.Errorf("public literal rejected: %v", err)
+	}
+}
+
+func TestClientRefusesLoopback(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	_, err = NewClient(0).Get("http://" + ln.Addr().String())
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("got %v, want ErrForbiddenAddress", err)
+	}
+}
@@ -0,0 +1,223 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 未知 kid 触发重新拉取 JWKS 的最小间隔
+const jwksRefreshInterval = time.Minute
+
+// IDToken 是校验通过的 ID Token 中用到的声明
+type IDToken struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type idTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+// Verify 校验 ID Token 的签名、iss、aud、azp、exp 和 nonce（OIDC Core 3.1.3.7）
+func (p *Provider) Verify(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.lookup(ctx, kid, token.Method.Alg())
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(p.discovery.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id_token: %w", err)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, errors.New("oidc: id_token azp does not match client")
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return nil, errors.New("oidc: id_token nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("oidc: id_token has no subject")
+	}
+
+	return &IDToken{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keyCache 缓存身份提供方的公钥，遇到未知 kid 时重新拉取，以跟上对方的密钥轮换
+type keyCache struct {
+	client  *http.Client
+	uri     string
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+func newKeyCache(client *http.Client, uri string) *keyCache {
+	return &keyCache{client: client, uri: uri}
+}
+
+func (kc *keyCache) lookup(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	key, ok := kc.find(kid)
+	if !ok && time.Since(kc.fetched) > jwksRefreshInterval {
+		if err := kc.refresh(ctx); err != nil {
+			return nil, err
+		}
+		key, ok = kc.find(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("oidc: no signing key for kid %q", kid)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return nil, errors.New("oidc: algorithm does not match key type")
+		}
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return nil, errors.New("oidc: algorithm does not match key type")
+		}
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return nil, errors.New("oidc: algorithm does not match key type")
+		}
+	}
+	return key, nil
+}
+
+// find 在只有一把密钥且 token 未带 kid 时直接使用该密钥
+func (kc *keyCache) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(kc.keys) == 1 {
+		for _, key := range kc.keys {
+			return key, true
+		}
+	}
+	key, ok := kc.keys[kid]
+	return key, ok
+}
+
+func (kc *keyCache) refresh(ctx context.Context) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, kc.client, kc.uri, &set); err != nil {
+		return fmt.Errorf("oidc: fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	kc.keys = keys
+	kc.fetched = time.Now()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,225 @@
+// Package salary 解析国内招聘中常见的薪资写法，例如 "25k*15"、"30-40K·16薪"、
+// "2.5w×14 + 签字费5w + 股票40w/4年"、"年包60万"，输出结构化的薪资组成
+package salary
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnrecognized 表示文本中找不到月薪或年薪
+var ErrUnrecognized = errors.New("salary: unrecognized format")
+
+// DefaultVestingYears 是股票未写归属年限时的默认值
+const DefaultVestingYears = 4
+
+// 月薪合理性阈值，按 Currency 计价，日元不做检查：
+// 没写月数时不低于 annualFloor 的金额按年薪理解（"40万"），超过 maxMonthly 的月薪视为无法识别
+const (
+	annualFloor = 100000
+	maxMonthly  = 200000
+)
+
+// Package 是解析结果，金额均以 Currency 计价
+type Package struct {
+	Currency          string
+	BaseMonthly       float64
+	BaseMonthlyMax    float64 // 区间写法的上限，非区间时为 0
+	Months            float64
+	SignOnBonus       float64
+	AnnualBonusTarget float64
+	StockGrant        float64
+	VestingYears      int
+	AllowancesMonthly float64
+}
+
+const amount = `(\d+(?:\.\d+)?)\s*(k|w|万|千)?`
+
+var (
+	replacer = strings.NewReplacer(
+		"＊", "*", "×", "*", "✕", "*",
+		"·", " ", "・", " ", "•", " ",
+		"～", "-", "~", "-", "－", "-", "—", "-", "到", "-", "至", "-",
+		"，", ",", "：", ":", "／", "/", "＋", "+",
+		"元", "",
+	)
+
+	signOnPattern    = regexp.MustCompile(`(?:签字费|签约奖金?|签约费|sign[- ]?on(?:\s*bonus)?)\s*:?\s*` + amount)
+	stockPattern     = regexp.MustCompile(`(?:股票|期权|股权|rsu|stock)\s*:?\s*` + amount + `(?:\s*/\s*(\d+)\s*年)?`)
+	allowancePattern = regexp.MustCompile(`(?:房补|餐补|交通补贴|补贴|津贴|allowance)\s*:?\s*` + amount + `(?:\s*/\s*月)?`)
+	bonusMonthsRegex = regexp.MustCompile(`(?:年终奖?|奖金|bonus)\s*:?\s*(\d+(?:\.\d+)?)\s*个?月`)
+	bonusPattern     = regexp.MustCompile(`(?:年终奖?|奖金|bonus)\s*:?\s*` + amount)
+	annualPattern    = regexp.MustCompile(`(?:年薪|年包|总包|package)\s*:?\s*` + amount)
+	annualSuffix     = regexp.MustCompile(amount + `\s*(?:年薪|年包|总包|/\s*年)`)
+	basePattern      = regexp.MustCompile(amount + `(?:\s*-\s*` + amount + `)?`)
+	monthsPattern    = regexp.MustCompile(`^\s*(?:\*|x)\s*(\d{1,2}(?:\.\d)?)`)
+	monthsToken      = regexp.MustCompile(`(\d{1,2}(?:\.\d)?)\s*薪`)
+	// mixedUnit 匹配 "2万5"、"2w5千"、"12k5" 这类单位后跟一位数字的写法，末尾捕获的非数字字符需要原样放回
+	mixedUnit = regexp.MustCompile(`(\d+)\s*(万|w|k)(\d)千?(\D|$)`)
+)
+
+var currencies = []struct {
+	keywords []string
+	code     string
+}{
+	{[]string{"hkd", "港币", "港元", "hk$"}, "HKD"},
+	{[]string{"usd", "美元", "美金", "$"}, "USD"},
+	{[]string{"sgd", "新币", "新加坡元"}, "SGD"},
+	{[]string{"eur", "欧元", "€"}, "EUR"},
+	{[]string{"jpy", "日元", "円"}, "JPY"},
+}
+
+// Parse 解析薪资文本。月薪没有单位且小于 1000 时按千元理解（"25*15" 即 25k*15）；
+// "2万5"、"12k5" 按 2.5 万、12.5k 理解；
+// 只有年薪（"年包60万"、"30w年薪"，或没写月数的大额数字）时按月数摊成月薪；年终奖写成 "N个月" 时按月薪下限折算
+func Parse(text string) (*Package, error) {
+	s := replacer.Replace(strings.ToLower(strings.TrimSpace(text)))
+	if s == "" {
+		return nil, ErrUnrecognized
+	}
+
+	p := &Package{Currency: detectCurrency(s)}
+	for _, c := range currencies {
+		for _, k := range c.keywords {
+			s = strings.ReplaceAll(s, k, " ")
+		}
+	}
+	s = strings.ReplaceAll(s, "rmb", " ")
+	s = strings.ReplaceAll(s, "cny", " ")
+	s = strings.ReplaceAll(s, "人民币", " ")
+	s = strings.ReplaceAll(s, "¥", " ")
+	s = strings.ReplaceAll(s, "￥", " ")
+	s = mixedUnit.ReplaceAllString(s, "$1.$3$2$4")
+
+	if m := signOnPattern.FindStringSubmatch(s); m != nil {
+		p.SignOnBonus = value(m[1], m[2], false)
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+	if m := stockPattern.FindStringSubmatch(s); m != nil {
+		p.StockGrant = value(m[1], m[2], false)
+		p.VestingYears = DefaultVestingYears
+		if m[3] != "" {
+			p.VestingYears, _ = strconv.Atoi(m[3])
+		}
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+	for _, m := range allowancePattern.FindAllStringSubmatch(s, -1) {
+		p.AllowancesMonthly += value(m[1], m[2], true)
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+	var bonusMonths float64
+	if m := bonusMonthsRegex.FindStringSubmatch(s); m != nil {
+		bonusMonths, _ = strconv.ParseFloat(m[1], 64)
+		s = strings.Replace(s, m[0], " ", 1)
+	} else if m := bonusPattern.FindStringSubmatch(s); m != nil {
+		p.AnnualBonusTarget = value(m[1], m[2], false)
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+
+	// "N薪" 可能写在月薪前面，例如 "15薪 25k"，先取出月数，避免被当作月薪或年包金额（"36万年包 15薪"）
+	if m := monthsToken.FindStringSubmatch(s); m != nil {
+		p.Months = parseMonths(m[1])
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+
+	var annual float64
+	if m := annualPattern.FindStringSubmatch(s); m != nil {
+		annual = value(m[1], m[2], false)
+		s = strings.Replace(s, m[0], " ", 1)
+	} else if m := annualSuffix.FindStringSubmatch(s); m != nil {
+		annual = value(m[1], m[2], false)
+		s = strings.Replace(s, m[0], " ", 1)
+	}
+
+	if loc := basePattern.FindStringSubmatchIndex(s); loc != nil && annual == 0 {
+		m := submatches(s, loc)
+		maxUnit := m[4]
+		minUnit := m[2]
+		if minUnit == "" {
+			minUnit = maxUnit
+		}
+		p.BaseMonthly = value(m[1], minUnit, true)
+		if m[3] != "" {
+			p.BaseMonthlyMax = value(m[3], maxUnit, true)
+		}
+		if mm := monthsPattern.FindStringSubmatch(s[loc[1]:]); mm != nil && p.Months == 0 {
+			p.Months = parseMonths(mm[1])
+		}
+		// 外币常按年薪报价，例如 "$150k"；人民币没写月数的大额数字也是年薪，例如 "40万"
+		floor := annualFloor
+		if p.Currency != "CNY" {
+			floor = 50000
+		}
+		if p.Months == 0 && p.Currency != "JPY" && p.BaseMonthly >= float64(floor) {
+			annual, p.BaseMonthly, p.BaseMonthlyMax = p.BaseMonthly, 0, 0
+		}
+	}
+	if p.Months == 0 {
+		p.Months = 12
+	}
+
+	if annual > 0 {
+		p.BaseMonthly = annual / p.Months
+	}
+	if p.BaseMonthly == 0 {
+		return nil, ErrUnrecognized
+	}
+	if p.BaseMonthlyMax != 0 && p.BaseMonthlyMax < p.BaseMonthly {
+		p.BaseMonthly, p.BaseMonthlyMax = p.BaseMonthlyMax, p.BaseMonthly
+	}
+	// 写明月数却仍远超常见月薪，多半是把年薪写成了 "30w*16"，宁可不识别也不写入放大十几倍的数字
+	if p.Currency != "JPY" && math.Max(p.BaseMonthly, p.BaseMonthlyMax) > maxMonthly {
+		return nil, ErrUnrecognized
+	}
+	if bonusMonths > 0 {
+		p.AnnualBonusTarget = bonusMonths * p.BaseMonthly
+	}
+	return p, nil
+}
+
+func detectCurrency(s string) string {
+	for _, c := range currencies {
+		for _, k := range c.keywords {
+			if strings.Contains(s, k) {
+				return c.code
+			}
+		}
+	}
+	return "CNY"
+}
+
+// value 按单位换算金额；monthly 为 true 时没有单位的小数字按千元理解
+func value(number, unit string, monthly bool) float64 {
+	v, _ := strconv.ParseFloat(number, 64)
+	switch unit {
+	case "k", "千":
+		return v * 1000
+	case "w", "万":
+		return v * 10000
+	}
+	if monthly && v < 1000 {
+		return v * 1000
+	}
+	return v
+}
+
+func parseMonths(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 12 || v > 24 {
+		return 0
+	}
+	return v
+}
+
+func submatches(s string, loc []int) []string {
+	out := make([]string, len(loc)/2)
+	for i := range out {
+		if loc[2*i] >= 0 {
+			out[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return out
+}
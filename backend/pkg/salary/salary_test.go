@@ -0,0 +1,62 @@
+package salary
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Package
+	}{
+		{"25k*15", Package{Currency: "CNY", BaseMonthly: 25000, Months: 15}},
+		{"25K×15", Package{Currency: "CNY", BaseMonthly: 25000, Months: 15}},
+		{"25*15", Package{Currency: "CNY", BaseMonthly: 25000, Months: 15}},
+		{"30-40K·16薪", Package{Currency: "CNY", BaseMonthly: 30000, BaseMonthlyMax: 40000, Months: 16}},
+		{"30k-40k 16薪", Package{Currency: "CNY", BaseMonthly: 30000, BaseMonthlyMax: 40000, Months: 16}},
+		{"15薪 25k", Package{Currency: "CNY", BaseMonthly: 25000, Months: 15}},
+		{"16薪，月薪30k", Package{Currency: "CNY", BaseMonthly: 30000, Months: 16}},
+		{"25k15薪", Package{Currency: "CNY", BaseMonthly: 25000, Months: 15}},
+		{"月薪2万5", Package{Currency: "CNY", BaseMonthly: 25000, Months: 12}},
+		{"2万5*14", Package{Currency: "CNY", BaseMonthly: 25000, Months: 14}},
+		{"2w5千 × 14", Package{Currency: "CNY", BaseMonthly: 25000, Months: 14}},
+		{"12k5*13", Package{Currency: "CNY", BaseMonthly: 12500, Months: 13}},
+		{"1万2-1万5·13薪", Package{Currency: "CNY", BaseMonthly: 12000, BaseMonthlyMax: 15000, Months: 13}},
+		{"2.5w×14 + 签字费5w + 股票40w/4年", Package{
+			Currency: "CNY", BaseMonthly: 25000, Months: 14, SignOnBonus: 50000, StockGrant: 400000, VestingYears: 4,
+		}},
+		{"35k*12，年终3个月，房补2k", Package{
+			Currency: "CNY", BaseMonthly: 35000, Months: 12, AnnualBonusTarget: 105000, AllowancesMonthly: 2000,
+		}},
+		{"年包60万", Package{Currency: "CNY", BaseMonthly: 50000, Months: 12}},
+		{"年包60万 15薪", Package{Currency: "CNY", BaseMonthly: 40000, Months: 15}},
+		{"30w年薪", Package{Currency: "CNY", BaseMonthly: 25000, Months: 12}},
+		{"36万年包 15薪", Package{Currency: "CNY", BaseMonthly: 24000, Months: 15}},
+		{"48w/年", Package{Currency: "CNY", BaseMonthly: 40000, Months: 12}},
+		{"48万", Package{Currency: "CNY", BaseMonthly: 40000, Months: 12}},
+		{"9万", Package{Currency: "CNY", BaseMonthly: 90000, Months: 12}},
+		{"$150k", Package{Currency: "USD", BaseMonthly: 12500, Months: 12}},
+		{"HKD 60k*13", Package{Currency: "HKD", BaseMonthly: 60000, Months: 13}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			if *got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	// "30w*16" 写明了月数，30 万月薪不合理，不能按年薪猜测
+	for _, in := range []string{"", "面议", "薪资待定", "30w*16", "25-35万·13薪"} {
+		if _, err := Parse(in); !errors.Is(err, ErrUnrecognized) {
+			t.Errorf("Parse(%q) error = %v, want ErrUnrecognized", in, err)
+		}
+	}
+}
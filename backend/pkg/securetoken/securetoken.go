@@ -0,0 +1,24 @@
+// Package securetoken 生成不透明的随机令牌，数据库只保存其 SHA-256 摘要
+package securetoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Generate 返回带前缀的明文令牌及其摘要，明文只应交给客户端一次
+func Generate(prefix string) (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = prefix + hex.EncodeToString(b)
+	return token, Hash(token), nil
+}
+
+// Hash 返回令牌的十六进制 SHA-256 摘要，用于入库和查找
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试密钥 "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// 附录 B 给出 8 位验证码，6 位验证码取其后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Counter(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeAcceptsLowercaseSecret(t *testing.T) {
+	got, err := Code(" "+strings.ToLower(rfcSecret)+" ", 1)
+	if err != nil || got != "287082" {
+		t.Fatalf("Code() = %s, %v, want 287082", got, err)
+	}
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Fatal("invalid secret accepted")
+	}
+}
+
+func TestVerifySkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Counter(now)
+	code := func(counter int64) string {
+		c, err := Code(rfcSecret, counter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		counter int64
+		skew    int
+		ok      bool
+	}{
+		{"current step", current, 0, true},
+		{"previous step without skew", current - 1, 0, false},
+		{"previous step", current - 1, 1, true},
+		{"next step", current + 1, 1, true},
+		{"two steps behind", current - 2, 1, false},
+		{"two steps ahead", current + 2, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Verify(rfcSecret, code(tt.counter), now, tt.skew)
+			if ok != tt.ok {
+				t.Fatalf("Verify() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && got != tt.counter {
+				t.Errorf("Verify() counter = %d, want %d", got, tt.counter)
+			}
+		})
+	}
+}
+
+func TestVerifyNormalizesInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"287082", " 287082 ", "287 082"} {
+		if _, ok := Verify(rfcSecret, code, now, 0); !ok {
+			t.Errorf("Verify(%q) rejected", code)
+		}
+	}
+	for _, code := range []string{"", "28708", "2870820", "000000"} {
+		if _, ok := Verify(rfcSecret, code, now, 0); ok {
+			t.Errorf("Verify(%q) accepted", code)
+		}
+	}
+}
+
+// 防重放依赖 Verify 返回验证码所属的时间步而不是当前时间步：
+// 同一验证码在容差窗口内再次提交时得到相同的时间步，调用方据此拒绝
+func TestVerifyCounterPreventsReplay(t *testing.T) {
+	issued := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, Counter(issued))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	accept := func(code string, now time.Time) bool {
+		counter, ok := Verify(rfcSecret, code, now, 1)
+		if !ok || counter <= last {
+			return false
+		}
+		last = counter
+		return true
+	}
+
+	if !accept(code, issued) {
+		t.Fatal("first use rejected")
+	}
+	if accept(code, issued) {
+		t.Fatal("replay in the same step accepted")
+	}
+	if accept(code, issued.Add(Period)) {
+		t.Fatal("replay in the next step accepted")
+	}
+
+	next, err := Code(rfcSecret, Counter(issued)+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !accept(next, issued.Add(Period)) {
+		t.Fatal("fresh code in the next step rejected")
+	}
+	// 已使用过更新的时间步后，容差窗口内较旧的验证码也不能再用
+	if accept(code, issued.Add(Period)) {
+		t.Fatal("older code accepted after a newer one")
+	}
+}
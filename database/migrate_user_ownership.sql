@@ -0,0 +1,35 @@
+-- 数据归属迁移：为 applications / interviews 增加 user_id，并把历史数据分配给指定用户
+-- 用法：修改下面的用户名后执行
+--   mysql -u root -p offermatrix < database/migrate_user_ownership.sql
+SET NAMES utf8mb4;
+
+USE offermatrix;
+
+SET @owner_username = 'your_username';
+SET @owner_id = (SELECT id FROM users WHERE username = @owner_username);
+
+-- 用户不存在时下面的 UPDATE 不会生效，先确认输出
+SELECT IF(@owner_id IS NULL, CONCAT('user not found: ', @owner_username), CONCAT('assigning rows to user ', @owner_id)) AS migration;
+
+-- 后端 AutoMigrate 已经添加过列时跳过 ADD COLUMN
+SET @has_app_col = (SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'applications' AND COLUMN_NAME = 'user_id');
+SET @sql = IF(@has_app_col = 0,
+    'ALTER TABLE applications ADD COLUMN user_id BIGINT NOT NULL DEFAULT 0 AFTER id, ADD INDEX idx_app_user_id (user_id)',
+    'SELECT 1');
+PREPARE stmt FROM @sql; EXECUTE stmt; DEALLOCATE PREPARE stmt;
+
+SET @has_interview_col = (SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'interviews' AND COLUMN_NAME = 'user_id');
+SET @sql = IF(@has_interview_col = 0,
+    'ALTER TABLE interviews ADD COLUMN user_id BIGINT NOT NULL DEFAULT 0 AFTER id, ADD INDEX idx_interview_user_id (user_id)',
+    'SELECT 1');
+PREPARE stmt FROM @sql; EXECUTE stmt; DEALLOCATE PREPARE stmt;
+
+-- 只认领无主数据（user_id = 0），重复执行是安全的
+UPDATE applications SET user_id = @owner_id WHERE user_id = 0 AND @owner_id IS NOT NULL;
+
+-- 面试跟随所属申请的归属
+UPDATE interviews i JOIN applications a ON a.id = i.application_id
+SET i.user_id = a.user_id
+WHERE i.user_id = 0 AND a.user_id <> 0;
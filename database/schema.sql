@@ -4,19 +4,46 @@ CREATE DATABASE IF NOT EXISTS offermatrix DEFAULT CHARACTER SET utf8mb4 COLLATE
 
 USE offermatrix;
 
+-- 用户表
+CREATE TABLE users (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    calendar_token_hash VARCHAR(64), -- 日历订阅密钥的 SHA-256 摘要
+    totp_secret VARCHAR(64), -- 两步验证密钥（Base32）
+    totp_enabled TINYINT(1) DEFAULT 0,
+    totp_last_counter BIGINT DEFAULT 0, -- 最近一次使用的时间步，防止验证码重放
+    oidc_issuer VARCHAR(191), -- 关联的企业身份提供方
+    oidc_subject VARCHAR(191),
+    role VARCHAR(20) NOT NULL DEFAULT 'user', -- user, admin
+    disabled_at DATETIME, -- 被管理员停用的时间
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_calendar_token_hash (calendar_token_hash),
+    UNIQUE KEY uniq_user_oidc (oidc_issuer, oidc_subject)
+);
+
 -- 公司申请表
 CREATE TABLE applications (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL, -- 所属用户
     company_name VARCHAR(100) NOT NULL,
     job_title VARCHAR(100),
-    current_status VARCHAR(20) DEFAULT 'IN_PROCESS', -- IN_PROCESS, OFFER, REJECTED
+    current_status VARCHAR(20) DEFAULT 'IN_PROCESS', -- WISHLIST, APPLIED, IN_PROCESS, OFFER, REJECTED, WITHDRAWN, GHOSTED
+    salary VARCHAR(100),
+    city VARCHAR(50),
+    job_description TEXT,
+    jd_analysis TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_app_user_id (user_id)
 );
 
 -- 面试记录表
 CREATE TABLE interviews (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL, -- 所属用户
     application_id BIGINT NOT NULL, -- FK to applications
     round_name VARCHAR(50) NOT NULL, -- e.g., "Technical Round 1", "HR Round"
     start_time DATETIME NOT NULL,
@@ -27,7 +54,400 @@ CREATE TABLE interviews (
     review_content TEXT, -- Markdown content for post-interview review
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_interview_user_id (user_id),
     INDEX idx_app_id (application_id),
     INDEX idx_start_time (start_time),
     FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE
 );
+
+-- 申请状态流转记录
+CREATE TABLE application_status_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    from_status VARCHAR(20), -- 创建时为空
+    to_status VARCHAR(20) NOT NULL,
+    reason VARCHAR(500),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_status_event_app_id (application_id),
+    FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+-- 面试提醒投递记录，唯一键包含开始时间快照，改期后重新提醒
+CREATE TABLE interview_reminders (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    interview_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    channel VARCHAR(20) NOT NULL, -- email, webhook
+    offset_minutes INT NOT NULL,
+    start_time DATETIME NOT NULL,
+    fire_at DATETIME NOT NULL,
+    status VARCHAR(20) DEFAULT 'PENDING', -- PENDING, SENDING, SENT, FAILED, SKIPPED
+    attempts INT DEFAULT 0,
+    last_error VARCHAR(500),
+    locked_until DATETIME,
+    sent_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_interview_reminder (interview_id, channel, offset_minutes, start_time),
+    INDEX idx_reminder_fire_at (fire_at),
+    INDEX idx_reminder_status (status)
+);
+
+-- 出站回调订阅
+CREATE TABLE webhook_subscriptions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(100) NOT NULL, -- HMAC-SHA256 签名密钥
+    events VARCHAR(500) NOT NULL, -- 逗号分隔，* 表示全部
+    description VARCHAR(200),
+    active TINYINT(1) DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_webhook_user_id (user_id)
+);
+
+-- 回调投递队列与日志
+CREATE TABLE webhook_deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    payload TEXT,
+    status VARCHAR(20) DEFAULT 'PENDING', -- PENDING, SENDING, SUCCEEDED, FAILED
+    attempts INT DEFAULT 0,
+    next_attempt_at DATETIME,
+    locked_until DATETIME,
+    response_status INT,
+    last_error VARCHAR(500),
+    delivered_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_delivery_subscription_id (subscription_id),
+    INDEX idx_delivery_status_next (status, next_attempt_at)
+);
+
+-- 登录会话，一次登录一个会话
+CREATE TABLE auth_sessions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    device VARCHAR(100), -- 由 User-Agent 识别的浏览器 / 系统
+    user_agent VARCHAR(255),
+    ip VARCHAR(64),
+    last_seen_at DATETIME,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    revoke_reason VARCHAR(50), -- logout, refresh_token_reuse, revoked_by_user, logout_others
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_session_user_id (user_id)
+);
+
+-- 轮换式 refresh token，只保存 SHA-256 摘要
+CREATE TABLE refresh_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    session_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME, -- 已轮换；再次使用视为重放并吊销整个会话
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_refresh_token_hash (token_hash),
+    INDEX idx_refresh_session_id (session_id)
+);
+
+-- 找回密码的一次性令牌，只保存 SHA-256 摘要
+CREATE TABLE password_reset_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_reset_token_hash (token_hash),
+    INDEX idx_reset_user_id (user_id)
+);
+
+-- 安全审计日志
+CREATE TABLE audit_logs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT, -- 0 表示无法归属到具体用户
+    action VARCHAR(50) NOT NULL, -- login.locked, login.ip_blocked
+    subject VARCHAR(255),
+    ip VARCHAR(64),
+    detail VARCHAR(500),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_user_id (user_id),
+    INDEX idx_audit_action (action),
+    INDEX idx_audit_created_at (created_at)
+);
+
+-- 登录失败计数（login.limiter = database 时使用）
+CREATE TABLE login_attempts (
+    `key` VARCHAR(191) PRIMARY KEY, -- user:<用户名> 或 ip:<地址>
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at DATETIME,
+    retry_at DATETIME,
+    locked TINYINT(1) NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- 两步验证恢复码，只保存 SHA-256 摘要
+CREATE TABLE recovery_codes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_recovery_user_id (user_id)
+);
+
+-- 个人访问令牌，只保存 SHA-256 摘要
+CREATE TABLE api_tokens (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    scope VARCHAR(20) NOT NULL, -- read-only, read-write
+    prefix VARCHAR(20), -- 明文前几位，便于辨认
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    last_used_at DATETIME,
+    last_used_ip VARCHAR(64),
+    revoked_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_api_token_hash (token_hash),
+    INDEX idx_api_token_user_id (user_id)
+);
+
+-- 单点登录过程状态：state 和一次性 handoff code 只保存 SHA-256 摘要
+CREATE TABLE oidc_login_states (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    state_hash VARCHAR(64) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL, -- PKCE verifier
+    redirect_path VARCHAR(255), -- 登录完成后返回的站内路径
+    callback_at DATETIME,
+    user_id BIGINT DEFAULT 0,
+    handoff_hash VARCHAR(64),
+    handoff_used_at DATETIME,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_oidc_state_hash (state_hash),
+    INDEX idx_oidc_handoff_hash (handoff_hash),
+    INDEX idx_oidc_expires_at (expires_at)
+);
+
+-- 求职者的工作区，每人最多一个
+CREATE TABLE workspaces (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    owner_id BIGINT NOT NULL,
+    name VARCHAR(100),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_workspace_owner (owner_id)
+);
+
+-- 工作区成员（教练）及邀请记录
+CREATE TABLE workspace_members (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    permissions VARCHAR(255) NOT NULL, -- 逗号分隔：view_pipeline, edit_pipeline, view_reviews, comment_reviews
+    status VARCHAR(20) NOT NULL, -- pending, accepted, declined, revoked, left
+    invited_by BIGINT,
+    responded_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_workspace_member (workspace_id, user_id),
+    INDEX idx_member_user_id (user_id)
+);
+
+-- 面试复盘评论，parent_id 为空的是主评论，可锚定复盘正文的行区间
+CREATE TABLE review_comments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    interview_id BIGINT NOT NULL,
+    owner_id BIGINT NOT NULL, -- 面试所属的求职者
+    author_id BIGINT NOT NULL,
+    parent_id BIGINT,
+    line_start INT,
+    line_end INT,
+    body TEXT,
+    edited_at DATETIME,
+    deleted_at DATETIME, -- 删除后保留占位，维持讨论结构
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_comment_interview_id (interview_id),
+    INDEX idx_comment_owner_id (owner_id),
+    INDEX idx_comment_author_id (author_id)
+);
+
+-- 评论中 @ 到的工作区成员
+CREATE TABLE review_comment_mentions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    comment_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_mention_comment_id (comment_id),
+    INDEX idx_mention_user_id (user_id)
+);
+
+-- 用户在每场面试下已读到的评论位置
+CREATE TABLE review_comment_reads (
+    user_id BIGINT NOT NULL,
+    interview_id BIGINT NOT NULL,
+    last_read_comment_id BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, interview_id)
+);
+
+-- 申请的结构化薪资，每个申请一条；月薪为区间时 base_monthly 为下限
+CREATE TABLE compensations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'CNY',
+    base_monthly DECIMAL(12,2) NOT NULL,
+    base_monthly_max DECIMAL(12,2) NOT NULL DEFAULT 0,
+    months DECIMAL(4,1) NOT NULL DEFAULT 12,
+    sign_on_bonus DECIMAL(14,2) NOT NULL DEFAULT 0,
+    annual_bonus_target DECIMAL(14,2) NOT NULL DEFAULT 0,
+    stock_grant DECIMAL(14,2) NOT NULL DEFAULT 0,
+    vesting_years INT NOT NULL DEFAULT 0,
+    vesting_schedule VARCHAR(100), -- 每年归属百分比，例如 25,25,25,25
+    allowances_monthly DECIMAL(12,2) NOT NULL DEFAULT 0,
+    raw VARCHAR(100), -- 解析来源的薪资文本
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_compensation_application_id (application_id),
+    INDEX idx_compensation_user_id (user_id)
+);
+
+-- 用户自定义的 offer 评价维度及权重
+CREATE TABLE offer_criteria (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    weight DECIMAL(6,2) NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_criterion_user_name (user_id, name)
+);
+
+-- 申请在各评价维度上的打分，0-10 分
+CREATE TABLE offer_scores (
+    application_id BIGINT NOT NULL,
+    criterion_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    score DECIMAL(4,2) NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, criterion_id),
+    INDEX idx_score_criterion_id (criterion_id),
+    INDEX idx_score_user_id (user_id)
+);
+
+-- 申请收到的 offer，每个申请一条
+CREATE TABLE offers (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    application_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING', -- PENDING, ACCEPTED, DECLINED, RESCINDED
+    received_at DATETIME,
+    expires_at DATETIME, -- 答复截止时间
+    decided_at DATETIME,
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_offer_application_id (application_id),
+    INDEX idx_offer_user_id (user_id),
+    INDEX idx_offer_expires_at (expires_at)
+);
+
+-- offer 谈判记录
+CREATE TABLE offer_negotiations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    offer_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- ask, counter
+    terms VARCHAR(100),
+    note TEXT,
+    occurred_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_negotiation_offer_id (offer_id),
+    INDEX idx_negotiation_user_id (user_id)
+);
+
+-- offer 答复截止提醒投递记录，唯一键包含截止时间快照
+CREATE TABLE offer_reminders (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    offer_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    channel VARCHAR(20) NOT NULL, -- email, webhook
+    offset_minutes INT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    fire_at DATETIME NOT NULL,
+    status VARCHAR(20) DEFAULT 'PENDING', -- PENDING, SENDING, SENT, FAILED, SKIPPED
+    attempts INT DEFAULT 0,
+    last_error VARCHAR(500),
+    locked_until DATETIME,
+    sent_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_offer_reminder (offer_id, channel, offset_minutes, expires_at),
+    INDEX idx_offer_reminder_fire_at (fire_at),
+    INDEX idx_offer_reminder_status (status)
+);
+
+-- 招聘方联系人：HR、猎头、面试官
+CREATE TABLE contacts (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    role VARCHAR(50),
+    company VARCHAR(100),
+    email VARCHAR(100),
+    phone VARCHAR(30),
+    wechat VARCHAR(50),
+    linkedin VARCHAR(200),
+    notes TEXT,
+    last_contacted_at DATETIME, -- 最近一次联系记录的时间，由联系记录维护
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_contact_user_id (user_id)
+);
+
+-- 联系人与申请的关联
+CREATE TABLE contact_applications (
+    contact_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contact_id, application_id),
+    INDEX idx_contact_app_application_id (application_id),
+    INDEX idx_contact_app_user_id (user_id)
+);
+
+-- 联系人与面试的关联
+CREATE TABLE contact_interviews (
+    contact_id BIGINT NOT NULL,
+    interview_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contact_id, interview_id),
+    INDEX idx_contact_interview_interview_id (interview_id),
+    INDEX idx_contact_interview_user_id (user_id)
+);
+
+-- 与联系人的联系记录
+CREATE TABLE contact_interactions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    contact_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- email, call, message, meeting, other
+    summary TEXT,
+    occurred_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_interaction_contact_id (contact_id),
+    INDEX idx_interaction_user_id (user_id)
+);
@@ -10,6 +10,8 @@ import WeekInterviews from './pages/WeekInterviews';
 import InProcess from './pages/InProcess';
 import Offers from './pages/Offers';
 import Login from './pages/Login';
+import ResetPassword from './pages/ResetPassword';
+import OIDCCallback from './pages/OIDCCallback';
 import { AuthProvider, useAuth } from './contexts/AuthContext';
 import './App.css';
 
@@ -166,6 +168,8 @@ function AppRoutes() {
   return (
     <Routes>
       <Route path="/login" element={<Login />} />
+      <Route path="/reset-password" element={<ResetPassword />} />
+      <Route path="/oidc/callback" element={<OIDCCallback />} />
       <Route
         path="/*"
         element={
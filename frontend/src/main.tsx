@@ -3,6 +3,9 @@ import { createRoot } from 'react-dom/client'
 import './index.css'
 import App from './App.tsx'
 
+// 旧版本把大模型 API Key 存在浏览器里，现已改由服务端配置，清理残留
+localStorage.removeItem('offermatrix_llm_config')
+
 createRoot(document.getElementById('root')!).render(
   <StrictMode>
     <App />
@@ -35,15 +35,23 @@ const statusColors: Record<string, string> = {
 };
 
 const appStatusLabels: Record<string, string> = {
+  WISHLIST: '意向',
+  APPLIED: '已投递',
   IN_PROCESS: '进行中',
   OFFER: '已拿 Offer',
   REJECTED: '已拒绝',
+  WITHDRAWN: '已放弃',
+  GHOSTED: '无回音',
 };
 
 const appStatusColors: Record<string, string> = {
+  WISHLIST: 'default',
+  APPLIED: 'cyan',
   IN_PROCESS: 'blue',
   OFFER: 'green',
   REJECTED: 'red',
+  WITHDRAWN: 'default',
+  GHOSTED: 'orange',
 };
 
 export default function ApplicationDetail() {
@@ -1,21 +1,45 @@
-import { useState } from 'react';
-import { useNavigate } from 'react-router-dom';
-import { Form, Input, Button, Card, Tabs, message } from 'antd';
-import { UserOutlined, LockOutlined } from '@ant-design/icons';
+import { useEffect, useState } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import { Form, Input, Button, Card, Tabs, Divider, message } from 'antd';
+import { UserOutlined, LockOutlined, MailOutlined, SafetyOutlined, KeyOutlined } from '@ant-design/icons';
 import { authApi } from '../services/api';
 import { useAuth } from '../contexts/AuthContext';
+import type { OIDCConfig } from '../types';
 
 export default function Login() {
   const navigate = useNavigate();
   const { login } = useAuth();
   const [loading, setLoading] = useState(false);
   const [activeTab, setActiveTab] = useState('login');
+  const [challengeToken, setChallengeToken] = useState('');
+  const [oidc, setOidc] = useState<OIDCConfig | null>(null);
+  const [searchParams, setSearchParams] = useSearchParams();
+
+  useEffect(() => {
+    authApi
+      .oidcConfig()
+      .then((res) => setOidc(res.data))
+      .catch(() => setOidc(null));
+  }, []);
+
+  // 单点登录失败时后端带着 oidc_error 跳回登录页
+  useEffect(() => {
+    const oidcError = searchParams.get('oidc_error');
+    if (oidcError) {
+      message.error(oidcError);
+      setSearchParams({}, { replace: true });
+    }
+  }, [searchParams, setSearchParams]);
 
   const handleLogin = async (values: { username: string; password: string }) => {
     try {
       setLoading(true);
       const res = await authApi.login(values);
-      login(res.data.token, res.data.user);
+      if ('two_factor_required' in res.data) {
+        setChallengeToken(res.data.challenge_token);
+        return;
+      }
+      login(res.data.token, res.data.refresh_token, res.data.user);
       message.success('登录成功');
       navigate('/');
     } catch (error: unknown) {
@@ -40,6 +64,38 @@ export default function Login() {
     }
   };
 
+  const handleTwoFactor = async (values: { code: string }) => {
+    try {
+      setLoading(true);
+      const res = await authApi.loginTwoFactor(challengeToken, values.code);
+      login(res.data.token, res.data.refresh_token, res.data.user);
+      message.success('登录成功');
+      navigate('/');
+    } catch (error: unknown) {
+      const err = error as { response?: { status?: number; data?: { error?: string } } };
+      message.error(err.response?.data?.error || '验证失败');
+      if (err.response?.status === 401 && err.response?.data?.error !== '验证码错误') {
+        setChallengeToken('');
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleForgot = async (values: { email: string }) => {
+    try {
+      setLoading(true);
+      await authApi.forgotPassword(values.email);
+      message.success('如果该邮箱已注册，重置链接已发送');
+      setActiveTab('login');
+    } catch (error: unknown) {
+      const err = error as { response?: { data?: { error?: string } } };
+      message.error(err.response?.data?.error || '发送失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
   return (
     <div
       className="min-h-screen flex items-center justify-center relative overflow-hidden"
@@ -101,86 +157,139 @@ export default function Login() {
           <p className="text-gray-500 mt-2">面试管理助手</p>
         </div>
 
-        <Tabs
-          activeKey={activeTab}
-          onChange={setActiveTab}
-          centered
-          items={[
-            {
-              key: 'login',
-              label: '登录',
-              children: (
-                <Form onFinish={handleLogin} layout="vertical" size="large">
-                  <Form.Item
-                    name="username"
-                    rules={[{ required: true, message: '请输入用户名' }]}
-                  >
-                    <Input prefix={<UserOutlined />} placeholder="用户名" />
-                  </Form.Item>
-                  <Form.Item
-                    name="password"
-                    rules={[{ required: true, message: '请输入密码' }]}
-                  >
-                    <Input.Password prefix={<LockOutlined />} placeholder="密码" />
-                  </Form.Item>
-                  <Form.Item>
-                    <Button type="primary" htmlType="submit" loading={loading} block>
-                      登录
-                    </Button>
-                  </Form.Item>
-                </Form>
-              ),
-            },
-            {
-              key: 'register',
-              label: '注册',
-              children: (
-                <Form onFinish={handleRegister} layout="vertical" size="large">
-                  <Form.Item
-                    name="username"
-                    rules={[
-                      { required: true, message: '请输入用户名' },
-                      { min: 3, message: '用户名至少3个字符' },
-                    ]}
-                  >
-                    <Input prefix={<UserOutlined />} placeholder="用户名" />
-                  </Form.Item>
-                  <Form.Item
-                    name="password"
-                    rules={[
-                      { required: true, message: '请输入密码' },
-                      { min: 6, message: '密码至少6个字符' },
-                    ]}
-                  >
-                    <Input.Password prefix={<LockOutlined />} placeholder="密码" />
-                  </Form.Item>
-                  <Form.Item
-                    name="confirmPassword"
-                    dependencies={['password']}
-                    rules={[
-                      { required: true, message: '请确认密码' },
-                      ({ getFieldValue }) => ({
-                        validator(_, value) {
-                          if (!value || getFieldValue('password') === value) {
-                            return Promise.resolve();
-                          }
-                          return Promise.reject(new Error('两次密码不一致'));
-                        },
-                      }),
-                    ]}
-                  >
-                    <Input.Password prefix={<LockOutlined />} placeholder="确认密码" />
-                  </Form.Item>
-                  <Form.Item>
-                    <Button type="primary" htmlType="submit" loading={loading} block>
-                      注册
-                    </Button>
-                  </Form.Item>
-                </Form>
-              ),
-            },
-          ]}
-        />
+        {challengeToken ? (
+          <Form onFinish={handleTwoFactor} layout="vertical" size="large">
+            <p className="text-gray-500 mb-4">请输入验证器应用中的 6 位验证码，或一枚恢复码</p>
+            <Form.Item name="code" rules={[{ required: true, message: '请输入验证码' }]}>
+              <Input prefix={<SafetyOutlined />} placeholder="验证码" autoFocus />
+            </Form.Item>
+            <Form.Item>
+              <Button type="primary" htmlType="submit" loading={loading} block>
+                验证
+              </Button>
+            </Form.Item>
+            <Button type="link" block onClick={() => setChallengeToken('')}>
+              返回
+            </Button>
+          </Form>
+        ) : (
+          <Tabs
+            activeKey={activeTab}
+            onChange={setActiveTab}
+            centered
+            items={[
+              {
+                key: 'login',
+                label: '登录',
+                children: (
+                  <Form onFinish={handleLogin} layout="vertical" size="large">
+                    <Form.Item
+                      name="username"
+                      rules={[{ required: true, message: '请输入用户名' }]}
+                    >
+                      <Input prefix={<UserOutlined />} placeholder="用户名" />
+                    </Form.Item>
+                    <Form.Item
+                      name="password"
+                      rules={[{ required: true, message: '请输入密码' }]}
+                    >
+                      <Input.Password prefix={<LockOutlined />} placeholder="密码" />
+                    </Form.Item>
+                    <Form.Item>
+                      <Button type="primary" htmlType="submit" loading={loading} block>
+                        登录
+                      </Button>
+                    </Form.Item>
+                    {oidc?.enabled && (
+                      <>
+                        <Divider plain>或</Divider>
+                        <Button
+                          icon={<KeyOutlined />}
+                          block
+                          onClick={() => {
+                            window.location.href = authApi.oidcLoginUrl();
+                          }}
+                        >
+                          {oidc.button_text}
+                        </Button>
+                      </>
+                    )}
+                  </Form>
+                ),
+              },
+              {
+                key: 'register',
+                label: '注册',
+                children: (
+                  <Form onFinish={handleRegister} layout="vertical" size="large">
+                    <Form.Item
+                      name="username"
+                      rules={[
+                        { required: true, message: '请输入用户名' },
+                        { min: 3, message: '用户名至少3个字符' },
+                      ]}
+                    >
+                      <Input prefix={<UserOutlined />} placeholder="用户名" />
+                    </Form.Item>
+                    <Form.Item
+                      name="password"
+                      rules={[
+                        { required: true, message: '请输入密码' },
+                        { min: 6, message: '密码至少6个字符' },
+                      ]}
+                    >
+                      <Input.Password prefix={<LockOutlined />} placeholder="密码" />
+                    </Form.Item>
+                    <Form.Item
+                      name="confirmPassword"
+                      dependencies={['password']}
+                      rules={[
+                        { required: true, message: '请确认密码' },
+                        ({ getFieldValue }) => ({
+                          validator(_, value) {
+                            if (!value || getFieldValue('password') === value) {
+                              return Promise.resolve();
+                            }
+                            return Promise.reject(new Error('两次密码不一致'));
+                          },
+                        }),
+                      ]}
+                    >
+                      <Input.Password prefix={<LockOutlined />} placeholder="确认密码" />
+                    </Form.Item>
+                    <Form.Item>
+                      <Button type="primary" htmlType="submit" loading={loading} block>
+                        注册
+                      </Button>
+                    </Form.Item>
+                  </Form>
+                ),
+              },
+              {
+                key: 'forgot',
+                label: '找回密码',
+                children: (
+                  <Form onFinish={handleForgot} layout="vertical" size="large">
+                    <Form.Item
+                      name="email"
+                      rules={[
+                        { required: true, message: '请输入注册邮箱' },
+                        { type: 'email', message: '邮箱格式不正确' },
+                      ]}
+                    >
+                      <Input prefix={<MailOutlined />} placeholder="注册邮箱" />
+                    </Form.Item>
+                    <Form.Item>
+                      <Button type="primary" htmlType="submit" loading={loading} block>
+                        发送重置链接
+                      </Button>
+                    </Form.Item>
+                  </Form>
+                ),
+              },
+            ]}
+          />
+        )}
       </Card>
     </div>
   );
@@ -0,0 +1,61 @@
+import { useEffect, useRef, useState } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import { Button, Card, Result, Spin } from 'antd';
+import { authApi } from '../services/api';
+import { useAuth } from '../contexts/AuthContext';
+
+export default function OIDCCallback() {
+  const navigate = useNavigate();
+  const { login } = useAuth();
+  const [searchParams] = useSearchParams();
+  const [error, setError] = useState('');
+  // 一次性 code 只能换一次，避免 StrictMode 下重复请求
+  const exchanged = useRef(false);
+
+  useEffect(() => {
+    if (exchanged.current) return;
+    exchanged.current = true;
+
+    const code = searchParams.get('code');
+    const redirect = searchParams.get('redirect') || '/';
+    if (!code) {
+      setError('登录链接无效');
+      return;
+    }
+
+    authApi
+      .oidcExchange(code)
+      .then((res) => {
+        login(res.data.token, res.data.refresh_token, res.data.user);
+        navigate(redirect.startsWith('/') && !redirect.startsWith('//') ? redirect : '/', { replace: true });
+      })
+      .catch((error: unknown) => {
+        const err = error as { response?: { data?: { error?: string } } };
+        setError(err.response?.data?.error || '登录失败');
+      });
+  }, [searchParams, login, navigate]);
+
+  return (
+    <div
+      className="min-h-screen flex items-center justify-center"
+      style={{
+        background: 'linear-gradient(135deg, #1e1b4b 0%, #4c1d95 30%, #831843 60%, #1e1b4b 100%)',
+      }}
+    >
+      <Card className="w-full max-w-md" style={{ borderRadius: 20 }}>
+        {error ? (
+          <Result
+            status="error"
+            title={error}
+            extra={<Button onClick={() => navigate('/login')}>返回登录</Button>}
+          />
+        ) : (
+          <div className="text-center py-8">
+            <Spin size="large" />
+            <p className="text-gray-500 mt-4">正在登录...</p>
+          </div>
+        )}
+      </Card>
+    </div>
+  );
+}
@@ -0,0 +1,82 @@
+import { useState } from 'react';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import { Form, Input, Button, Card, Result, message } from 'antd';
+import { LockOutlined } from '@ant-design/icons';
+import { authApi } from '../services/api';
+
+export default function ResetPassword() {
+  const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get('token') || '';
+  const [loading, setLoading] = useState(false);
+
+  const handleReset = async (values: { password: string }) => {
+    try {
+      setLoading(true);
+      await authApi.resetPassword(token, values.password);
+      message.success('密码已重置，请重新登录');
+      navigate('/login');
+    } catch (error: unknown) {
+      const err = error as { response?: { data?: { error?: string } } };
+      message.error(err.response?.data?.error || '重置密码失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div
+      className="min-h-screen flex items-center justify-center"
+      style={{
+        background: 'linear-gradient(135deg, #1e1b4b 0%, #4c1d95 30%, #831843 60%, #1e1b4b 100%)',
+      }}
+    >
+      <Card className="w-full max-w-md" style={{ borderRadius: 20 }}>
+        {token ? (
+          <>
+            <h2 className="text-xl font-bold text-center mb-6">设置新密码</h2>
+            <Form onFinish={handleReset} layout="vertical" size="large">
+              <Form.Item
+                name="password"
+                rules={[
+                  { required: true, message: '请输入新密码' },
+                  { min: 6, message: '密码至少6个字符' },
+                ]}
+              >
+                <Input.Password prefix={<LockOutlined />} placeholder="新密码" />
+              </Form.Item>
+              <Form.Item
+                name="confirmPassword"
+                dependencies={['password']}
+                rules={[
+                  { required: true, message: '请确认密码' },
+                  ({ getFieldValue }) => ({
+                    validator(_, value) {
+                      if (!value || getFieldValue('password') === value) {
+                        return Promise.resolve();
+                      }
+                      return Promise.reject(new Error('两次密码不一致'));
+                    },
+                  }),
+                ]}
+              >
+                <Input.Password prefix={<LockOutlined />} placeholder="确认新密码" />
+              </Form.Item>
+              <Form.Item>
+                <Button type="primary" htmlType="submit" loading={loading} block>
+                  重置密码
+                </Button>
+              </Form.Item>
+            </Form>
+          </>
+        ) : (
+          <Result
+            status="warning"
+            title="重置链接无效"
+            extra={<Button onClick={() => navigate('/login')}>返回登录</Button>}
+          />
+        )}
+      </Card>
+    </div>
+  );
+}
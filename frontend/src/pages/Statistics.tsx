@@ -25,24 +25,31 @@ import {
   AreaChart,
   Area,
 } from 'recharts';
-import type { Interview, Application } from '../types';
-import { interviewApi, applicationApi } from '../services/api';
+import type { ApplicationStatus, Stats } from '../types';
+import { statsApi } from '../services/api';
+
+const statusMeta: Record<ApplicationStatus, { name: string; color: string }> = {
+  WISHLIST: { name: '意向', color: '#94a3b8' },
+  APPLIED: { name: '已投递', color: '#06b6d4' },
+  IN_PROCESS: { name: '进行中', color: '#3b82f6' },
+  OFFER: { name: 'Offer', color: '#22c55e' },
+  REJECTED: { name: '已挂', color: '#ef4444' },
+  WITHDRAWN: { name: '已放弃', color: '#a1a1aa' },
+  GHOSTED: { name: '无回音', color: '#f97316' },
+};
+
+const percent = (rate: number) => (rate * 100).toFixed(1);
 
 export default function Statistics() {
-  const [interviews, setInterviews] = useState<Interview[]>([]);
-  const [applications, setApplications] = useState<Application[]>([]);
+  const [data, setData] = useState<Stats | null>(null);
   const [loading, setLoading] = useState(true);
   const [trendPeriod, setTrendPeriod] = useState<'week' | 'month'>('week');
 
   useEffect(() => {
     const fetchData = async () => {
       try {
-        const [interviewRes, appRes] = await Promise.all([
-          interviewApi.list(),
-          applicationApi.list(),
-        ]);
-        setInterviews(interviewRes.data);
-        setApplications(appRes.data);
+        const res = await statsApi.get();
+        setData(res.data);
       } finally {
         setLoading(false);
       }
@@ -50,106 +57,56 @@ export default function Statistics() {
     fetchData();
   }, []);
 
-  // 基础统计
+  // 基础统计，由服务端聚合
   const stats = useMemo(() => {
-    const totalInterviews = interviews.length;
-    const totalCompanies = applications.length;
-    const offers = applications.filter((a) => a.current_status === 'OFFER').length;
-    const rejected = applications.filter((a) => a.current_status === 'REJECTED').length;
-    const inProcess = applications.filter((a) => a.current_status === 'IN_PROCESS').length;
-    const offerRate = totalCompanies > 0 ? ((offers / totalCompanies) * 100).toFixed(1) : '0';
-
-    // 已复盘的面试数量
-    const reviewedCount = interviews.filter((i) => i.review_content && i.review_content.trim()).length;
-    const reviewRate = totalInterviews > 0 ? ((reviewedCount / totalInterviews) * 100).toFixed(1) : '0';
-
-    // 单日最多面试
-    const dateCountMap: Record<string, { count: number; date: string }> = {};
-    interviews.forEach((i) => {
-      const date = i.start_time.split('T')[0];
-      if (!dateCountMap[date]) {
-        dateCountMap[date] = { count: 0, date };
-      }
-      dateCountMap[date].count++;
-    });
-    const maxDayRecord = Object.values(dateCountMap).sort((a, b) => b.count - a.count)[0];
-
+    const countOf = (status: ApplicationStatus) =>
+      data?.status_counts.find((s) => s.status === status)?.count || 0;
     return {
-      totalInterviews,
-      totalCompanies,
-      offers,
-      rejected,
-      inProcess,
-      offerRate,
-      reviewRate,
-      maxDayCount: maxDayRecord?.count || 0,
-      maxDayDate: maxDayRecord?.date || '-',
+      totalInterviews: data?.total_interviews || 0,
+      totalCompanies: data?.total_applications || 0,
+      // 曾经拿到的 Offer，包括之后拒绝或被撤回的
+      offers: data?.offer_count || 0,
+      rejected: countOf('REJECTED'),
+      inProcess: countOf('IN_PROCESS'),
+      offerRate: percent(data?.offer_rate || 0),
+      reviewRate: percent(data?.review_completion_rate || 0),
+      avgDaysToOffer: data?.avg_days_to_offer != null ? data.avg_days_to_offer.toFixed(1) : '-',
     };
-  }, [interviews, applications]);
+  }, [data]);
 
-  // 时间趋势数据
+  // 时间趋势数据：最近 8 周或 6 个月
   const trendData = useMemo(() => {
-    if (interviews.length === 0) return [];
-
-    const now = new Date();
-    const data: { name: string; count: number }[] = [];
-
+    if (!data) return [];
     if (trendPeriod === 'week') {
-      // 最近8周
-      for (let i = 7; i >= 0; i--) {
-        const weekStart = new Date(now);
-        weekStart.setDate(now.getDate() - now.getDay() - i * 7);
-        weekStart.setHours(0, 0, 0, 0);
-        const weekEnd = new Date(weekStart);
-        weekEnd.setDate(weekStart.getDate() + 7);
-
-        const count = interviews.filter((interview) => {
-          const date = new Date(interview.start_time);
-          return date >= weekStart && date < weekEnd;
-        }).length;
-
-        const label = `${weekStart.getMonth() + 1}/${weekStart.getDate()}`;
-        data.push({ name: label, count });
-      }
-    } else {
-      // 最近6个月
-      for (let i = 5; i >= 0; i--) {
-        const monthStart = new Date(now.getFullYear(), now.getMonth() - i, 1);
-        const monthEnd = new Date(now.getFullYear(), now.getMonth() - i + 1, 0, 23, 59, 59);
-
-        const count = interviews.filter((interview) => {
-          const date = new Date(interview.start_time);
-          return date >= monthStart && date <= monthEnd;
-        }).length;
-
-        const label = `${monthStart.getMonth() + 1}月`;
-        data.push({ name: label, count });
-      }
+      return (data.interviews_by_week || []).slice(-8).map(({ period, count }) => {
+        const [, month, day] = period.split('-');
+        return { name: `${Number(month)}/${Number(day)}`, count };
+      });
     }
-
-    return data;
-  }, [interviews, trendPeriod]);
+    return (data.interviews_by_month || []).slice(-6).map(({ period, count }) => ({
+      name: `${Number(period.split('-')[1])}月`,
+      count,
+    }));
+  }, [data, trendPeriod]);
 
   // 公司状态分布
   const statusData = useMemo(() => {
-    return [
-      { name: '进行中', value: stats.inProcess, color: '#3b82f6' },
-      { name: 'Offer', value: stats.offers, color: '#22c55e' },
-      { name: '已挂', value: stats.rejected, color: '#ef4444' },
-    ].filter((d) => d.value > 0);
-  }, [stats]);
+    return (data?.status_counts || [])
+      .filter((s) => s.count > 0)
+      .map((s) => ({
+        name: statusMeta[s.status]?.name || s.status,
+        value: s.count,
+        color: statusMeta[s.status]?.color || '#94a3b8',
+      }));
+  }, [data]);
 
   // 面试轮次分布
   const roundData = useMemo(() => {
-    const roundCount: Record<string, number> = {};
-    interviews.forEach((i) => {
-      const round = i.round_name || '其他';
-      roundCount[round] = (roundCount[round] || 0) + 1;
-    });
-    return Object.entries(roundCount)
-      .map(([name, count]) => ({ name, count }))
-      .sort((a, b) => b.count - a.count);
-  }, [interviews]);
+    return (data?.round_distribution || []).map((r) => ({
+      name: r.round_name || '其他',
+      count: r.count,
+    }));
+  }, [data]);
 
   if (loading) {
     return (
@@ -171,7 +128,7 @@ export default function Statistics() {
           { icon: <RocketOutlined />, title: '进行中', value: stats.inProcess, gradient: 'linear-gradient(135deg, #3b82f6 0%, #0ea5e9 100%)', glow: 'rgba(59, 130, 246, 0.25)' },
           { icon: <PercentageOutlined />, title: 'Offer 率', value: stats.offerRate, suffix: '%', gradient: 'linear-gradient(135deg, #10b981 0%, #059669 100%)', glow: 'rgba(16, 185, 129, 0.25)' },
           { icon: <CheckCircleOutlined />, title: '复盘完成率', value: stats.reviewRate, suffix: '%', gradient: 'linear-gradient(135deg, #8b5cf6 0%, #a855f7 100%)', glow: 'rgba(139, 92, 246, 0.25)' },
-          { icon: <FireOutlined />, title: '单日最多面试', value: stats.maxDayCount, suffix: '场', gradient: 'linear-gradient(135deg, #f97316 0%, #ef4444 100%)', glow: 'rgba(249, 115, 22, 0.25)', extra: stats.maxDayDate },
+          { icon: <FireOutlined />, title: '平均拿 Offer 用时', value: stats.avgDaysToOffer, suffix: stats.avgDaysToOffer === '-' ? '' : '天', gradient: 'linear-gradient(135deg, #f97316 0%, #ef4444 100%)', glow: 'rgba(249, 115, 22, 0.25)' },
         ].map((item, index) => (
           <Col xs={12} sm={8} md={6} key={index}>
             <div
@@ -188,7 +145,6 @@ export default function Statistics() {
               <div className="text-2xl mb-2" style={{ opacity: 0.8 }}>{item.icon}</div>
               <div className="text-3xl font-bold">{item.value}{item.suffix}</div>
               <div className="text-sm opacity-80">{item.title}</div>
-              {item.extra && <div className="text-xs opacity-60 mt-1">{item.extra}</div>}
             </div>
           </Col>
         ))}
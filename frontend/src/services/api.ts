@@ -1,6 +1,8 @@
 import axios from 'axios';
 import type {
   Application,
+  ApplicationStatusEvent,
+  Compensation,
   Interview,
   CreateApplicationRequest,
   UpdateApplicationRequest,
@@ -9,7 +11,39 @@ import type {
   LoginRequest,
   RegisterRequest,
   LoginResponse,
+  TokenResponse,
+  TwoFactorChallengeResponse,
+  TwoFactorSetupResponse,
+  OIDCConfig,
+  AuthSession,
+  APIToken,
+  AdminUser,
+  UserUsage,
+  AuditLog,
+  Paged,
+  Workspace,
+  WorkspaceMember,
+  WorkspaceMembership,
+  WorkspacePermission,
+  ReviewComment,
+  UnreadComments,
+  OfferCriterion,
+  CriterionRequest,
+  OfferScoreItem,
+  OfferComparison,
+  Offer,
+  OfferNegotiation,
+  SaveOfferRequest,
+  CreateNegotiationRequest,
+  Contact,
+  ContactRequest,
+  ContactDetail,
+  ContactInteraction,
+  CreateInteractionRequest,
   User,
+  ParsedInvitation,
+  Stats,
+  JDAnalysisResult,
 } from '../types';
 
 const api = axios.create({
@@ -28,14 +62,67 @@ api.interceptors.request.use((config) => {
   return config;
 });
 
-// 响应拦截器：处理 401 错误
+function clearSession() {
+  localStorage.removeItem('token');
+  localStorage.removeItem('refresh_token');
+  localStorage.removeItem('user');
+}
+
+// 同一时间只发起一次刷新，并发的 401 请求共享结果
+let refreshing: Promise<string> | null = null;
+
+function refreshAccessToken(): Promise<string> {
+  if (!refreshing) {
+    const refreshToken = localStorage.getItem('refresh_token');
+    refreshing = (
+      refreshToken
+        ? axios
+            .post<TokenResponse>('/api/auth/refresh', { refresh_token: refreshToken })
+            .then((res) => {
+              localStorage.setItem('token', res.data.token);
+              localStorage.setItem('refresh_token', res.data.refresh_token);
+              return res.data.token;
+            })
+        : Promise.reject(new Error('no refresh token'))
+    ).finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+}
+
+// 这些接口的 401 表示凭证本身错误，不需要刷新
+const noRefreshUrls = [
+  '/auth/login',
+  '/auth/login/2fa',
+  '/auth/register',
+  '/auth/refresh',
+  '/auth/logout',
+  '/auth/password/forgot',
+  '/auth/password/reset',
+  '/auth/oidc/exchange',
+];
+
+// 响应拦截器：access token 过期时先尝试刷新，失败再回到登录页
 api.interceptors.response.use(
   (response) => response,
-  (error) => {
-    if (error.response?.status === 401) {
-      localStorage.removeItem('token');
-      localStorage.removeItem('user');
-      window.location.href = '/login';
+  async (error) => {
+    const original = error.config;
+    if (
+      error.response?.status === 401 &&
+      original &&
+      !original._retry &&
+      !noRefreshUrls.includes(original.url)
+    ) {
+      original._retry = true;
+      try {
+        const token = await refreshAccessToken();
+        original.headers.Authorization = `Bearer ${token}`;
+        return api(original);
+      } catch {
+        clearSession();
+        window.location.href = '/login';
+      }
     }
     return Promise.reject(error);
   }
@@ -44,12 +131,62 @@ api.interceptors.response.use(
 // Auth API
 export const authApi = {
   login: (data: LoginRequest) =>
-    api.post<LoginResponse>('/auth/login', data),
+    api.post<LoginResponse | TwoFactorChallengeResponse>('/auth/login', data),
+
+  loginTwoFactor: (challengeToken: string, code: string) =>
+    api.post<LoginResponse>('/auth/login/2fa', { challenge_token: challengeToken, code }),
+
+  twoFactorStatus: () =>
+    api.get<{ enabled: boolean; recovery_codes_remaining: number }>('/auth/2fa'),
+
+  setupTwoFactor: () => api.post<TwoFactorSetupResponse>('/auth/2fa/setup'),
+
+  enableTwoFactor: (code: string) =>
+    api.post<{ recovery_codes: string[] }>('/auth/2fa/enable', { code }),
+
+  disableTwoFactor: (password: string, code: string) =>
+    api.post('/auth/2fa/disable', { password, code }),
+
+  regenerateRecoveryCodes: (code: string) =>
+    api.post<{ recovery_codes: string[] }>('/auth/2fa/recovery-codes', { code }),
 
   register: (data: RegisterRequest) =>
     api.post<User>('/auth/register', data),
 
+  oidcConfig: () => api.get<OIDCConfig>('/auth/oidc/config'),
+
+  // 单点登录需要整页跳转到身份提供方，不能走 axios
+  oidcLoginUrl: (redirect = '/') => `/api/auth/oidc/login?redirect=${encodeURIComponent(redirect)}`,
+
+  oidcExchange: (code: string) => api.post<LoginResponse>('/auth/oidc/exchange', { code }),
+
   me: () => api.get<User>('/auth/me'),
+
+  logout: (refreshToken: string) =>
+    api.post('/auth/logout', { refresh_token: refreshToken }),
+
+  changePassword: (oldPassword: string, newPassword: string) =>
+    api.put('/auth/password', { old_password: oldPassword, new_password: newPassword }),
+
+  forgotPassword: (email: string) => api.post('/auth/password/forgot', { email }),
+
+  resetPassword: (token: string, newPassword: string) =>
+    api.post('/auth/password/reset', { token, new_password: newPassword }),
+
+  deleteAccount: (password: string) => api.delete('/auth/me', { data: { password } }),
+
+  sessions: () => api.get<AuthSession[]>('/auth/sessions'),
+
+  revokeSession: (id: number) => api.delete(`/auth/sessions/${id}`),
+
+  revokeOtherSessions: () => api.delete<{ revoked: number }>('/auth/sessions'),
+
+  apiTokens: () => api.get<APIToken[]>('/auth/tokens'),
+
+  createApiToken: (data: { name: string; scope: APIToken['scope']; expires_in_days?: number }) =>
+    api.post<APIToken & { token: string }>('/auth/tokens', data),
+
+  revokeApiToken: (id: number) => api.delete(`/auth/tokens/${id}`),
 };
 
 // Applications API
@@ -64,6 +201,9 @@ export const applicationApi = {
 
   get: (id: number) => api.get<Application>(`/applications/${id}`),
 
+  timeline: (id: number) =>
+    api.get<ApplicationStatusEvent[]>(`/applications/${id}/timeline`),
+
   create: (data: CreateApplicationRequest) =>
     api.post<Application>('/applications', data),
 
@@ -71,6 +211,9 @@ export const applicationApi = {
     api.put<Application>(`/applications/${id}`, data),
 
   delete: (id: number) => api.delete(`/applications/${id}`),
+
+  parseSalary: (text: string) =>
+    api.post<Compensation>('/salary/parse', { text }),
 };
 
 // Interviews API
@@ -94,4 +237,196 @@ export const interviewApi = {
   delete: (id: number) => api.delete(`/interviews/${id}`),
 };
 
+// AI API（服务端代理大模型调用）
+export const aiApi = {
+  parseInvitation: (text: string) =>
+    api.post<ParsedInvitation>('/ai/parse-invitation', { text }),
+
+  analyzeJD: (applicationId: number, jobDescription?: string) =>
+    api.post<{ application: Application; analysis: JDAnalysisResult }>(
+      `/applications/${applicationId}/analyze-jd`,
+      { job_description: jobDescription }
+    ),
+};
+
+export const statsApi = {
+  get: (params?: { start?: string; end?: string }) =>
+    api.get<Stats>('/stats', { params }),
+};
+
+// 教练工作区 API
+export const workspaceApi = {
+  mine: () =>
+    api.get<{ workspace: Workspace | null; members: WorkspaceMember[] }>('/workspaces/mine'),
+
+  invite: (username: string, permissions: WorkspacePermission[]) =>
+    api.post<WorkspaceMember>('/workspaces/mine/members', { username, permissions }),
+
+  updateMember: (id: number, permissions: WorkspacePermission[]) =>
+    api.put(`/workspaces/mine/members/${id}`, { permissions }),
+
+  revokeMember: (id: number) => api.delete(`/workspaces/mine/members/${id}`),
+
+  joined: () => api.get<WorkspaceMembership[]>('/workspaces/joined'),
+
+  accept: (id: number) => api.post(`/workspaces/joined/${id}/accept`),
+
+  decline: (id: number) => api.post(`/workspaces/joined/${id}/decline`),
+
+  leave: (id: number) => api.delete(`/workspaces/joined/${id}`),
+
+  // 教练查看求职者数据：在原有接口上带 workspace_id
+  applications: (workspaceId: number, keyword?: string) =>
+    api.get<Application[]>('/applications', { params: { workspace_id: workspaceId, keyword } }),
+
+  application: (workspaceId: number, id: number) =>
+    api.get<Application>(`/applications/${id}`, { params: { workspace_id: workspaceId } }),
+
+  interviews: (workspaceId: number, start?: string, end?: string) =>
+    api.get<Interview[]>('/interviews', { params: { workspace_id: workspaceId, start, end } }),
+};
+
+// 面试复盘评论 API，查看教练工作区中的面试时带 workspaceId
+export const commentApi = {
+  list: (interviewId: number, workspaceId?: number) =>
+    api.get<ReviewComment[]>(`/interviews/${interviewId}/comments`, {
+      params: { workspace_id: workspaceId },
+    }),
+
+  create: (
+    interviewId: number,
+    data: { body: string; parent_id?: number; line_start?: number; line_end?: number },
+    workspaceId?: number
+  ) =>
+    api.post<ReviewComment>(`/interviews/${interviewId}/comments`, data, {
+      params: { workspace_id: workspaceId },
+    }),
+
+  update: (interviewId: number, commentId: number, body: string, workspaceId?: number) =>
+    api.put<ReviewComment>(`/interviews/${interviewId}/comments/${commentId}`, { body }, {
+      params: { workspace_id: workspaceId },
+    }),
+
+  delete: (interviewId: number, commentId: number, workspaceId?: number) =>
+    api.delete(`/interviews/${interviewId}/comments/${commentId}`, {
+      params: { workspace_id: workspaceId },
+    }),
+
+  markRead: (interviewId: number, workspaceId?: number) =>
+    api.post(`/interviews/${interviewId}/comments/read`, null, {
+      params: { workspace_id: workspaceId },
+    }),
+
+  unread: () => api.get<UnreadComments>('/comments/unread'),
+};
+
+// Offer 对比、截止与谈判 API
+export const offerApi = {
+  compare: (ids: number[], currency?: string, workspaceId?: number) =>
+    api.get<OfferComparison>('/offers/compare', {
+      params: { ids: ids.join(','), currency, workspace_id: workspaceId },
+    }),
+
+  criteria: () => api.get<OfferCriterion[]>('/offers/criteria'),
+
+  createCriterion: (data: CriterionRequest) =>
+    api.post<OfferCriterion>('/offers/criteria', data),
+
+  updateCriterion: (id: number, data: CriterionRequest) =>
+    api.put<OfferCriterion>(`/offers/criteria/${id}`, data),
+
+  deleteCriterion: (id: number) => api.delete(`/offers/criteria/${id}`),
+
+  scores: (applicationId: number) =>
+    api.get<(OfferScoreItem & { application_id: number })[]>(`/applications/${applicationId}/scores`),
+
+  updateScores: (applicationId: number, scores: OfferScoreItem[]) =>
+    api.put(`/applications/${applicationId}/scores`, { scores }),
+
+  get: (applicationId: number, workspaceId?: number) =>
+    api.get<Offer>(`/applications/${applicationId}/offer`, {
+      params: { workspace_id: workspaceId },
+    }),
+
+  save: (applicationId: number, data: SaveOfferRequest) =>
+    api.put<Offer>(`/applications/${applicationId}/offer`, data),
+
+  delete: (applicationId: number) => api.delete(`/applications/${applicationId}/offer`),
+
+  addNegotiation: (applicationId: number, data: CreateNegotiationRequest) =>
+    api.post<OfferNegotiation>(`/applications/${applicationId}/offer/negotiations`, data),
+
+  deleteNegotiation: (applicationId: number, negotiationId: number) =>
+    api.delete(`/applications/${applicationId}/offer/negotiations/${negotiationId}`),
+
+  expiring: (days?: number, workspaceId?: number) =>
+    api.get<Offer[]>('/offers/expiring', { params: { days, workspace_id: workspaceId } }),
+};
+
+// 招聘方联系人 API
+export const contactApi = {
+  list: (keyword?: string) => api.get<Contact[]>('/contacts', { params: { keyword } }),
+
+  get: (id: number) => api.get<ContactDetail>(`/contacts/${id}`),
+
+  create: (data: ContactRequest) => api.post<Contact>('/contacts', data),
+
+  update: (id: number, data: ContactRequest) => api.put<Contact>(`/contacts/${id}`, data),
+
+  delete: (id: number) => api.delete(`/contacts/${id}`),
+
+  followUp: (days?: number) => api.get<Contact[]>('/contacts/follow-up', { params: { days } }),
+
+  linkApplication: (id: number, applicationId: number) =>
+    api.post(`/contacts/${id}/applications/${applicationId}`),
+
+  unlinkApplication: (id: number, applicationId: number) =>
+    api.delete(`/contacts/${id}/applications/${applicationId}`),
+
+  linkInterview: (id: number, interviewId: number) =>
+    api.post(`/contacts/${id}/interviews/${interviewId}`),
+
+  unlinkInterview: (id: number, interviewId: number) =>
+    api.delete(`/contacts/${id}/interviews/${interviewId}`),
+
+  interactions: (id: number) => api.get<ContactInteraction[]>(`/contacts/${id}/interactions`),
+
+  addInteraction: (id: number, data: CreateInteractionRequest) =>
+    api.post<ContactInteraction>(`/contacts/${id}/interactions`, data),
+
+  deleteInteraction: (id: number, interactionId: number) =>
+    api.delete(`/contacts/${id}/interactions/${interactionId}`),
+
+  byApplication: (applicationId: number) =>
+    api.get<Contact[]>(`/applications/${applicationId}/contacts`),
+
+  byInterview: (interviewId: number) => api.get<Contact[]>(`/interviews/${interviewId}/contacts`),
+};
+
+// 管理后台 API，仅管理员可用
+export const adminApi = {
+  users: (params?: { keyword?: string; page?: number; page_size?: number }) =>
+    api.get<Paged<AdminUser>>('/admin/users', { params }),
+
+  user: (id: number) => api.get<{ user: AdminUser; usage: UserUsage }>(`/admin/users/${id}`),
+
+  updateRole: (id: number, role: AdminUser['role']) =>
+    api.put<AdminUser>(`/admin/users/${id}/role`, { role }),
+
+  disable: (id: number) => api.post<AdminUser>(`/admin/users/${id}/disable`),
+
+  enable: (id: number) => api.post<AdminUser>(`/admin/users/${id}/enable`),
+
+  resetPassword: (id: number, newPassword?: string) =>
+    api.post<{ temporary_password?: string }>(`/admin/users/${id}/reset-password`, {
+      new_password: newPassword,
+    }),
+
+  impersonate: (id: number) =>
+    api.post<{ token: string; expires_in: number; user: User }>(`/admin/users/${id}/impersonate`),
+
+  auditLogs: (params?: { user_id?: number; action?: string; page?: number; page_size?: number }) =>
+    api.get<Paged<AuditLog>>('/admin/audit-logs', { params }),
+};
+
 export default api;
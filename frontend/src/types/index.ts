@@ -1,14 +1,67 @@
+export type ApplicationStatus =
+  | 'WISHLIST'
+  | 'APPLIED'
+  | 'IN_PROCESS'
+  | 'OFFER'
+  | 'REJECTED'
+  | 'WITHDRAWN'
+  | 'GHOSTED';
+
 export interface Application {
   id: number;
   company_name: string;
   job_title: string;
-  current_status: 'IN_PROCESS' | 'OFFER' | 'REJECTED';
+  current_status: ApplicationStatus;
   salary?: string;
+  city?: string;
   job_description?: string;
   jd_analysis?: string;
   created_at: string;
   updated_at: string;
   interviews?: Interview[];
+  compensation?: Compensation;
+  offer?: Offer;
+}
+
+// 结构化薪资，月薪为区间时合计按下限 base_monthly 计算
+export interface Compensation {
+  id: number;
+  application_id: number;
+  currency: string;
+  base_monthly: number;
+  base_monthly_max: number;
+  months: number;
+  sign_on_bonus: number;
+  annual_bonus_target: number;
+  stock_grant: number;
+  vesting_years: number;
+  vesting_schedule: string;
+  allowances_monthly: number;
+  raw: string;
+  first_year_total: number;
+  annualized_total: number;
+}
+
+export interface CompensationRequest {
+  currency?: string;
+  base_monthly: number;
+  base_monthly_max?: number;
+  months?: number;
+  sign_on_bonus?: number;
+  annual_bonus_target?: number;
+  stock_grant?: number;
+  vesting_years?: number;
+  vesting_schedule?: string;
+  allowances_monthly?: number;
+}
+
+export interface ApplicationStatusEvent {
+  id: number;
+  application_id: number;
+  from_status: ApplicationStatus | '';
+  to_status: ApplicationStatus;
+  reason?: string;
+  created_at: string;
 }
 
 export interface Interview {
@@ -29,6 +82,9 @@ export interface Interview {
 export interface User {
   id: number;
   username: string;
+  email?: string;
+  role?: 'user' | 'admin';
+  two_factor_enabled?: boolean;
   created_at: string;
 }
 
@@ -44,9 +100,158 @@ export interface RegisterRequest {
 
 export interface LoginResponse {
   token: string;
+  refresh_token: string;
+  expires_in: number;
   user: User;
 }
 
+export interface OIDCConfig {
+  enabled: boolean;
+  button_text: string;
+}
+
+export interface AuthSession {
+  id: number;
+  device: string;
+  user_agent: string;
+  ip: string;
+  created_at: string;
+  last_seen_at: string;
+  expires_at: string;
+  current: boolean;
+}
+
+// 开启两步验证的账号登录时先返回 challenge token
+export interface TwoFactorChallengeResponse {
+  two_factor_required: true;
+  challenge_token: string;
+  expires_in: number;
+}
+
+export interface TwoFactorSetupResponse {
+  secret: string;
+  otpauth_url: string;
+}
+
+export interface APIToken {
+  id: number;
+  name: string;
+  scope: 'read-only' | 'read-write';
+  prefix: string;
+  expires_at: string;
+  last_used_at: string | null;
+  last_used_ip: string;
+  created_at: string;
+}
+
+export interface AdminUser {
+  id: number;
+  username: string;
+  email: string;
+  role: 'user' | 'admin';
+  two_factor_enabled: boolean;
+  sso: boolean;
+  disabled_at: string | null;
+  created_at: string;
+}
+
+export interface UserUsage {
+  applications: number;
+  interviews: number;
+  webhooks: number;
+  api_tokens: number;
+  active_sessions: number;
+  last_seen_at: string | null;
+}
+
+export interface AuditLog {
+  id: number;
+  user_id: number;
+  action: string;
+  subject: string;
+  ip: string;
+  detail: string;
+  created_at: string;
+}
+
+export interface Paged<T> {
+  items: T[];
+  total: number;
+}
+
+export type WorkspacePermission =
+  | 'view_pipeline'
+  | 'edit_pipeline'
+  | 'view_reviews'
+  | 'comment_reviews';
+
+export type WorkspaceMemberStatus = 'pending' | 'accepted' | 'declined' | 'revoked' | 'left';
+
+export interface Workspace {
+  id: number;
+  owner_id: number;
+  name: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface WorkspaceMember {
+  id: number;
+  user_id: number;
+  username: string;
+  permissions: WorkspacePermission[];
+  status: WorkspaceMemberStatus;
+  responded_at: string | null;
+  created_at: string;
+}
+
+export interface WorkspaceMembership {
+  id: number;
+  workspace_id: number;
+  workspace_name: string;
+  owner_id: number;
+  owner_username: string;
+  permissions: WorkspacePermission[];
+  status: WorkspaceMemberStatus;
+  responded_at: string | null;
+  created_at: string;
+}
+
+export interface ReviewComment {
+  id: number;
+  interview_id: number;
+  owner_id: number;
+  author_id: number;
+  author_username: string;
+  parent_id: number | null;
+  line_start: number | null;
+  line_end: number | null;
+  body: string;
+  mentions: string[];
+  edited_at: string | null;
+  deleted_at: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface UnreadComments {
+  total: number;
+  mentions: number;
+  interviews: {
+    interview_id: number;
+    owner_id: number;
+    workspace_id: number;
+    unread: number;
+    mentions: number;
+  }[];
+}
+
+export interface TokenResponse {
+  token: string;
+  refresh_token: string;
+  expires_in: number;
+}
+
 export interface CreateApplicationRequest {
   company_name: string;
   job_title?: string;
@@ -56,10 +261,13 @@ export interface CreateApplicationRequest {
 export interface UpdateApplicationRequest {
   company_name?: string;
   job_title?: string;
-  current_status?: 'IN_PROCESS' | 'OFFER' | 'REJECTED';
+  current_status?: ApplicationStatus;
+  status_reason?: string;
   salary?: string;
+  city?: string;
   job_description?: string;
   jd_analysis?: string;
+  compensation?: CompensationRequest;
 }
 
 export interface CreateInterviewRequest {
@@ -92,3 +300,204 @@ export interface ParsedInterview {
   meeting_link: string | null;
   confidence: number;
 }
+
+// 服务端 AI 解析结果
+export interface ParsedInvitation {
+  company_name: string;
+  round_name: string;
+  start_time: string | null;
+  end_time: string | null;
+  meeting_link: string;
+  confidence: number;
+  matched_application_id: number;
+}
+
+export interface JDAnalysisResult {
+  responsibilities: string[];
+  skills: string[];
+  advantages: string[];
+  concerns: string[];
+  salary_analysis: string;
+  markdown: string;
+}
+
+// 服务端统计数据，比率为 0-1 之间的小数
+export interface Stats {
+  total_applications: number;
+  status_counts: { status: ApplicationStatus; count: number }[];
+  offer_count: number;
+  offer_rate: number;
+  total_interviews: number;
+  finished_interviews: number;
+  reviewed_interviews: number;
+  review_completion_rate: number;
+  interviews_by_week: { period: string; count: number }[];
+  interviews_by_month: { period: string; count: number }[];
+  round_distribution: { round_name: string; count: number }[];
+  avg_days_to_offer: number | null;
+}
+
+// Offer 及谈判记录
+export type OfferStatus = 'PENDING' | 'ACCEPTED' | 'DECLINED' | 'RESCINDED';
+
+export interface OfferNegotiation {
+  id: number;
+  offer_id: number;
+  kind: 'ask' | 'counter';
+  terms: string;
+  note: string;
+  occurred_at: string;
+  created_at: string;
+  annualized_total: number | null;
+}
+
+export interface Offer {
+  id: number;
+  application_id: number;
+  status: OfferStatus;
+  received_at: string | null;
+  expires_at: string | null;
+  decided_at: string | null;
+  notes: string;
+  created_at: string;
+  updated_at: string;
+  negotiations?: OfferNegotiation[];
+  application?: Application;
+}
+
+export interface SaveOfferRequest {
+  status?: OfferStatus;
+  received_at?: string;
+  expires_at?: string;
+  notes?: string;
+}
+
+export interface CreateNegotiationRequest {
+  kind: 'ask' | 'counter';
+  terms?: string;
+  note?: string;
+  occurred_at?: string;
+}
+
+// Offer 对比
+export interface OfferCriterion {
+  id: number;
+  name: string;
+  weight: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface CriterionRequest {
+  name: string;
+  weight: number;
+}
+
+export interface OfferScoreItem {
+  criterion_id: number;
+  score: number;
+}
+
+export interface ComparedCompensation {
+  base_monthly: number;
+  base_monthly_max: number;
+  months: number;
+  sign_on_bonus: number;
+  annual_bonus_target: number;
+  stock_grant: number;
+  vesting_schedule: number[];
+  allowances_monthly: number;
+  annual_cash: number;
+}
+
+export interface CompensationTotals {
+  year_1: number;
+  year_2: number;
+  year_4: number;
+  annualized: number;
+}
+
+// 对比矩阵中的一列，金额已折算成 OfferComparison.currency
+export interface OfferColumn {
+  application_id: number;
+  company_name: string;
+  job_title: string;
+  current_status: ApplicationStatus;
+  city: string;
+  offer_status: OfferStatus | '';
+  response_deadline: string | null;
+  original_currency?: string;
+  rate?: number;
+  compensation: ComparedCompensation | null;
+  totals: CompensationTotals | null;
+  scores: OfferScoreItem[];
+  weighted_score: number | null;
+}
+
+export interface OfferComparison {
+  currency: string;
+  criteria: OfferCriterion[];
+  offers: OfferColumn[];
+}
+
+// 联系人
+export type InteractionKind = 'email' | 'call' | 'message' | 'meeting' | 'other';
+
+export interface Contact {
+  id: number;
+  name: string;
+  role: string;
+  company: string;
+  email: string;
+  phone: string;
+  wechat: string;
+  linkedin: string;
+  notes: string;
+  last_contacted_at: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ContactRequest {
+  name: string;
+  role?: string;
+  company?: string;
+  email?: string;
+  phone?: string;
+  wechat?: string;
+  linkedin?: string;
+  notes?: string;
+}
+
+export interface ContactInteraction {
+  id: number;
+  contact_id: number;
+  kind: InteractionKind;
+  summary: string;
+  occurred_at: string;
+  created_at: string;
+}
+
+export interface CreateInteractionRequest {
+  kind: InteractionKind;
+  summary?: string;
+  occurred_at?: string;
+}
+
+export interface ContactDetail extends Contact {
+  applications: {
+    id: number;
+    company_name: string;
+    job_title: string;
+    current_status: string;
+  }[];
+  interviews: {
+    id: number;
+    application_id: number;
+    company_name: string;
+    round_name: string;
+    start_time: string;
+    status: string;
+  }[];
+  interactions: ContactInteraction[];
+}